@@ -0,0 +1,285 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/zabbix/protocol"
+	"github.com/AlekSi/zabbix/sender"
+)
+
+const (
+	// Defaults as in agent configuration.
+	DefaultRefreshInterval = 2 * time.Minute
+	DefaultBufferSend      = 5 * time.Second
+	DefaultBufferSize      = 100
+)
+
+// Active check returned by server.
+type ActiveCheck struct {
+	Key   string
+	Delay time.Duration
+}
+
+// Implements fmt.Stringer.
+func (c ActiveCheck) String() string {
+	return fmt.Sprintf("%s every %s", c.Key, c.Delay)
+}
+
+// Active checks agent: gets item list from server, collects values with registered handlers
+// and sends them back. Use it for items of type zabbix.ZabbixAgentActive.
+type Active struct {
+	Handlers
+
+	ServerAddr      string        // server or proxy address like "zabbix:10051"
+	Host            string        // host name as configured in Zabbix
+	HostMetadata    string        // used by autoregistration
+	RefreshInterval time.Duration // how often item list is requested
+	BufferSend      time.Duration // how long values are kept in buffer before sending
+	BufferSize      int           // values are sent when buffer is full; oldest are dropped if sending fails
+	Timeout         time.Duration // for each exchange with server, zero means no timeout
+	Compress        bool          // compress packets with zlib, Zabbix 4.0+
+	Logger          *log.Logger   // nil by default
+
+	session  string
+	lastId   int64
+	checks   map[string]*scheduled
+	buffer   []activeValue
+	buffered time.Time
+}
+
+type scheduled struct {
+	delay time.Duration
+	next  time.Time
+}
+
+type activeChecksRequest struct {
+	Request      string `json:"request"`
+	Host         string `json:"host"`
+	HostMetadata string `json:"host_metadata,omitempty"`
+}
+
+type activeChecksResponse struct {
+	Response string `json:"response"`
+	Info     string `json:"info"`
+	Data     []struct {
+		Key   string      `json:"key"`
+		Delay interface{} `json:"delay"` // number before Zabbix 3.4, string with suffix after
+	} `json:"data"`
+}
+
+type activeValue struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	State int    `json:"state,omitempty"` // 1 for not supported items
+	Id    int64  `json:"id"`
+	Clock int64  `json:"clock"`
+	Ns    int    `json:"ns"`
+}
+
+type agentDataRequest struct {
+	Request string        `json:"request"`
+	Session string        `json:"session"`
+	Data    []activeValue `json:"data"`
+	Clock   int64         `json:"clock"`
+	Ns      int           `json:"ns"`
+}
+
+// Creates new active agent with default intervals and buffer size.
+func NewActive(serverAddr, host string) *Active {
+	return &Active{
+		ServerAddr:      serverAddr,
+		Host:            host,
+		RefreshInterval: DefaultRefreshInterval,
+		BufferSend:      DefaultBufferSend,
+		BufferSize:      DefaultBufferSize,
+		Timeout:         DefaultTimeout,
+	}
+}
+
+func (a *Active) printf(format string, v ...interface{}) {
+	if a.Logger != nil {
+		a.Logger.Printf(format, v...)
+	}
+}
+
+// Requests list of active checks for a.Host from server.
+func (a *Active) Checks() (checks []ActiveCheck, err error) {
+	req := activeChecksRequest{Request: "active checks", Host: a.Host, HostMetadata: a.HostMetadata}
+	var res activeChecksResponse
+	err = protocol.Exchange(a.ServerAddr, a.Timeout, a.Compress, req, &res)
+	if err != nil {
+		return
+	}
+	if res.Response != "success" {
+		err = &sender.Failed{Response: res.Response, Info: res.Info}
+		return
+	}
+
+	for _, d := range res.Data {
+		delay, ok := parseDelay(d.Delay)
+		if !ok {
+			a.printf("Skipping %s: unsupported delay %v", d.Key, d.Delay)
+			continue
+		}
+		checks = append(checks, ActiveCheck{Key: d.Key, Delay: delay})
+	}
+	return
+}
+
+// Parses update interval like 30, "30" or "1m"; flexible intervals after ";" are ignored.
+func parseDelay(delay interface{}) (d time.Duration, ok bool) {
+	var s string
+	switch delay := delay.(type) {
+	case float64:
+		s = strconv.FormatFloat(delay, 'f', -1, 64)
+	case string:
+		s = strings.SplitN(delay, ";", 2)[0]
+	default:
+		return
+	}
+	if s == "" {
+		return
+	}
+
+	unit := time.Second
+	switch s[len(s)-1] {
+	case 's':
+		s = s[:len(s)-1]
+	case 'm':
+		unit, s = time.Minute, s[:len(s)-1]
+	case 'h':
+		unit, s = time.Hour, s[:len(s)-1]
+	case 'd':
+		unit, s = 24*time.Hour, s[:len(s)-1]
+	case 'w':
+		unit, s = 7*24*time.Hour, s[:len(s)-1]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return
+	}
+	return time.Duration(n) * unit, true
+}
+
+// Runs agent until ctx is done: refreshes checks, collects values when they are due and sends them.
+// Errors are logged and retried. Buffered values are sent before return.
+func (a *Active) Run(ctx context.Context) error {
+	if a.session == "" {
+		b := make([]byte, 16)
+		rand.Read(b)
+		a.session = hex.EncodeToString(b)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var refreshed time.Time
+	for {
+		now := time.Now()
+		if refreshed.IsZero() || now.Sub(refreshed) >= a.RefreshInterval {
+			refreshed = now
+			checks, err := a.Checks()
+			if err != nil {
+				a.printf("Failed to get active checks: %s", err)
+			} else {
+				a.schedule(checks, now)
+			}
+		}
+
+		a.collect(now)
+		if len(a.buffer) > 0 && (len(a.buffer) >= a.BufferSize || now.Sub(a.buffered) >= a.BufferSend) {
+			a.flush()
+		}
+
+		select {
+		case <-ctx.Done():
+			if len(a.buffer) > 0 {
+				a.flush()
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Replaces checks, keeping schedule of ones with unchanged delay.
+func (a *Active) schedule(checks []ActiveCheck, now time.Time) {
+	old := a.checks
+	a.checks = make(map[string]*scheduled, len(checks))
+	for _, c := range checks {
+		if s := old[c.Key]; s != nil && s.delay == c.Delay {
+			a.checks[c.Key] = s
+			continue
+		}
+		a.checks[c.Key] = &scheduled{delay: c.Delay, next: now}
+	}
+}
+
+func (a *Active) collect(now time.Time) {
+	for key, s := range a.checks {
+		if now.Before(s.next) {
+			continue
+		}
+		s.next = now.Add(s.delay)
+
+		v := activeValue{Host: a.Host, Key: key}
+		value, err := a.get(key)
+		if err != nil {
+			v.State = 1
+			v.Value = err.(*NotSupported).Message
+		} else {
+			v.Value = value
+		}
+
+		t := time.Now()
+		a.lastId++
+		v.Id, v.Clock, v.Ns = a.lastId, t.Unix(), t.Nanosecond()
+		if len(a.buffer) == 0 {
+			a.buffered = t
+		}
+		a.buffer = append(a.buffer, v)
+	}
+
+	if a.BufferSize > 0 && len(a.buffer) > a.BufferSize {
+		a.buffer = a.buffer[len(a.buffer)-a.BufferSize:]
+	}
+}
+
+// Gets value like Handlers.Get, but reports panic of handler as not supported item,
+// so it affects only that check.
+func (a *Active) get(key string) (value string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.printf("Handler of %s panicked: %v", key, r)
+			value, err = "", &NotSupported{key, fmt.Sprintf("Handler panicked: %v.", r)}
+		}
+	}()
+	return a.Get(key)
+}
+
+func (a *Active) flush() {
+	t := time.Now()
+	req := agentDataRequest{Request: "agent data", Session: a.session, Data: a.buffer, Clock: t.Unix(), Ns: t.Nanosecond()}
+	var res sender.Response
+	err := protocol.Exchange(a.ServerAddr, a.Timeout, a.Compress, req, &res)
+	if err == nil {
+		var r sender.Result
+		r, err = res.Result()
+		if err == nil && r.Failed > 0 {
+			a.printf("Server failed to process %d of %d values", r.Failed, r.Total)
+		}
+	}
+	if err != nil {
+		a.printf("Failed to send %d values: %s", len(a.buffer), err)
+		return
+	}
+	a.buffer = nil
+}
@@ -0,0 +1,145 @@
+package agent_test
+
+import (
+	. "."
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+type agentRequest struct {
+	Request      string `json:"request"`
+	Host         string `json:"host"`
+	HostMetadata string `json:"host_metadata"`
+	Data         []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		State int    `json:"state"`
+		Id    int64  `json:"id"`
+	} `json:"data"`
+}
+
+// Starts local stand-in for server or proxy returning given active checks.
+func serveActive(t *testing.T, checks []map[string]interface{}) (addr string, requests chan agentRequest) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests = make(chan agentRequest, 100)
+	go func() {
+		defer l.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := protocol.Read(conn, protocol.MaxDataSize)
+			if err != nil {
+				t.Error(err)
+				conn.Close()
+				continue
+			}
+			var req agentRequest
+			json.Unmarshal(b, &req)
+			requests <- req
+
+			var res interface{}
+			switch req.Request {
+			case "active checks":
+				res = map[string]interface{}{"response": "success", "data": checks}
+			case "agent data":
+				res = map[string]string{"response": "success", "info": "processed: 1; failed: 0; total: 1; seconds spent: 0.000055"}
+			}
+			b, _ = json.Marshal(res)
+			protocol.Write(conn, b, false)
+			conn.Close()
+		}
+	}()
+	return l.Addr().String(), requests
+}
+
+func TestActive(t *testing.T) {
+	checks := []map[string]interface{}{
+		{"key": "app.requests", "delay": "1s", "lastlogsize": 0, "mtime": 0},
+		{"key": "app.missing", "delay": 30, "lastlogsize": 0, "mtime": 0},
+		{"key": "app.panic", "delay": "1s", "lastlogsize": 0, "mtime": 0},
+		{"key": "app.flexible", "delay": "0;10/1-5,09:00-18:00", "lastlogsize": 0, "mtime": 0},
+	}
+	addr, requests := serveActive(t, checks)
+
+	a := NewActive(addr, "host")
+	a.HostMetadata = "Linux go"
+	a.BufferSend = 0
+	a.Handle("app.requests", func(params []string) (interface{}, error) {
+		return 42, nil
+	})
+	a.Handle("app.panic", func(params []string) (interface{}, error) {
+		panic("lala")
+	})
+
+	res, err := a.Checks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 || res[0].Delay != time.Second || res[1].Delay != 30*time.Second {
+		t.Errorf("Bad checks: %v", res)
+	}
+	<-requests
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+
+	req := <-requests
+	if req.Request != "active checks" || req.Host != "host" || req.HostMetadata != "Linux go" {
+		t.Errorf("Bad request: %#v", req)
+	}
+
+	values := make(map[string]int)
+	var lastId int64
+	timeout := time.After(5 * time.Second)
+	for values["app.requests"] < 2 {
+		select {
+		case req = <-requests:
+		case <-timeout:
+			t.Fatalf("Timeout, got values %v", values)
+		}
+		if req.Request != "agent data" {
+			t.Fatalf("Bad request: %#v", req)
+		}
+		for _, d := range req.Data {
+			values[d.Key]++
+			if d.Id <= lastId {
+				t.Errorf("Bad id: %#v", d)
+			}
+			lastId = d.Id
+			switch d.Key {
+			case "app.requests":
+				if d.Value != "42" || d.State != 0 {
+					t.Errorf("Bad value: %#v", d)
+				}
+			case "app.missing":
+				if d.Value != UnsupportedKey || d.State != 1 {
+					t.Errorf("Bad value: %#v", d)
+				}
+			case "app.panic":
+				if d.Value != "Handler panicked: lala." || d.State != 1 {
+					t.Errorf("Bad value: %#v", d)
+				}
+			}
+		}
+	}
+	// run continues after handler panic
+	if values["app.missing"] != 1 || values["app.panic"] < 2 {
+		t.Errorf("Bad values: %v", values)
+	}
+
+	cancel()
+	if err = <-done; err != context.Canceled {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,215 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+const (
+	// Default timeout of passive check, as in agent configuration.
+	DefaultTimeout = 3 * time.Second
+
+	notSupported = "ZBX_NOTSUPPORTED"
+	errorPrefix  = "ZBX_ERROR"
+)
+
+// Error returned for ZBX_NOTSUPPORTED (and ZBX_ERROR) agent responses.
+type NotSupported struct {
+	Key     string
+	Message string // empty for old agents which don't send it
+}
+
+func (e *NotSupported) Error() string {
+	return fmt.Sprintf("Item %s is not supported: %s", e.Key, e.Message)
+}
+
+type WrongInterfaceType zabbix.InterfaceType
+
+func (e WrongInterfaceType) Error() string {
+	return fmt.Sprintf("Expected agent interface (type %d), got type %d.", zabbix.Agent, int(e))
+}
+
+// Passive checks client.
+type Client struct {
+	Timeout time.Duration // for whole check, zero means no timeout
+
+	// Use JSON passive checks protocol of Zabbix 7.0+ agents.
+	// Client falls back to plain protocol if agent doesn't understand it.
+	JSON bool
+}
+
+type jsonRequest struct {
+	Request string            `json:"request"`
+	Data    []jsonRequestData `json:"data"`
+}
+
+type jsonRequestData struct {
+	Key     string `json:"key"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type jsonResponse struct {
+	Version string `json:"version"`
+	Variant int    `json:"variant"` // 1 for agent, 2 for agent 2
+	Error   string `json:"error"`
+	Data    []struct {
+		Value *string `json:"value"`
+		Error *string `json:"error"`
+	} `json:"data"`
+}
+
+// Creates new client with default timeout.
+func NewClient() *Client {
+	return &Client{Timeout: DefaultTimeout}
+}
+
+// Gets value of item key from agent at addr (like "host:10050") with default client.
+func Get(addr, key string) (value string, err error) {
+	return NewClient().Get(addr, key)
+}
+
+// Returns address of agent interface, using IP or DNS name as set by UseIP.
+func InterfaceAddr(iface zabbix.HostInterface) (addr string, err error) {
+	if iface.Type != zabbix.Agent {
+		err = WrongInterfaceType(iface.Type)
+		return
+	}
+
+	host := iface.DNS
+	if iface.UseIP == 1 {
+		host = iface.IP
+	}
+	port := iface.Port
+	if port == "" {
+		port = "10050"
+	}
+	addr = net.JoinHostPort(host, port)
+	return
+}
+
+// Gets value of item key from agent interface of a host.
+func (c *Client) GetFromInterface(iface zabbix.HostInterface, key string) (value string, err error) {
+	addr, err := InterfaceAddr(iface)
+	if err != nil {
+		return
+	}
+	return c.Get(addr, key)
+}
+
+// Gets value of item key from agent at addr (like "host:10050").
+// Returns *NotSupported error if agent can't get value.
+func (c *Client) Get(addr, key string) (value string, err error) {
+	if c.JSON {
+		var fallback bool
+		value, fallback, err = c.getJSON(addr, key)
+		if !fallback {
+			return
+		}
+	}
+
+	b, err := c.exchange(addr, []byte(key))
+	if err != nil {
+		return
+	}
+	return parseValue(key, string(b))
+}
+
+// Returns fallback = true if agent doesn't support JSON protocol.
+func (c *Client) getJSON(addr, key string) (value string, fallback bool, err error) {
+	req := jsonRequest{Request: "passive checks", Data: []jsonRequestData{{Key: key}}}
+	if c.Timeout > 0 {
+		// agent accepts whole seconds only, so shorter timeouts are rounded up
+		req.Data[0].Timeout = fmt.Sprintf("%ds", int((c.Timeout+time.Second-1)/time.Second))
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	b, err = c.exchange(addr, b)
+	if err != nil {
+		return
+	}
+	if !bytes.HasPrefix(b, []byte("{")) {
+		fallback = true
+		return
+	}
+
+	var res jsonResponse
+	if err = json.Unmarshal(b, &res); err != nil {
+		return
+	}
+	switch {
+	case res.Error != "":
+		err = &NotSupported{key, res.Error}
+	case len(res.Data) != 1:
+		err = fmt.Errorf("Expected exactly one value, got %d.", len(res.Data))
+	case res.Data[0].Error != nil:
+		err = &NotSupported{key, *res.Data[0].Error}
+	case res.Data[0].Value != nil:
+		value = *res.Data[0].Value
+	}
+	return
+}
+
+func (c *Client) exchange(addr string, request []byte) (response []byte, err error) {
+	conn, err := net.DialTimeout("tcp", addr, c.Timeout)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if c.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.Timeout))
+	}
+
+	if err = protocol.Write(conn, request, false); err != nil {
+		return
+	}
+	return readResponse(conn)
+}
+
+// Reads response with header or, for old agents, plain text until connection is closed.
+func readResponse(r io.Reader) (data []byte, err error) {
+	head := make([]byte, len(protocol.Header)+1)
+	n, err := io.ReadFull(r, head)
+	if err == nil && bytes.Equal(head[:len(protocol.Header)], protocol.Header) {
+		return protocol.ReadAfterHeader(r, head[len(protocol.Header)], protocol.MaxDataSize)
+	}
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return
+	}
+
+	rest, err := ioutil.ReadAll(io.LimitReader(r, protocol.MaxDataSize))
+	data = append(head[:n], rest...)
+	if err == nil && len(data) == 0 {
+		// connection was closed without response, for example because of agent's Server option
+		err = io.ErrUnexpectedEOF
+	}
+	data = bytes.TrimRight(data, "\n")
+	return
+}
+
+// Parses plain protocol response: value or ZBX_NOTSUPPORTED with optional message after NUL byte.
+func parseValue(key, response string) (value string, err error) {
+	for _, prefix := range []string{notSupported, errorPrefix} {
+		if !strings.HasPrefix(response, prefix) {
+			continue
+		}
+		rest := response[len(prefix):]
+		if rest == "" || rest[0] == 0 {
+			err = &NotSupported{key, strings.TrimRight(strings.TrimPrefix(rest, "\x00"), "\x00")}
+			return
+		}
+	}
+	value = response
+	return
+}
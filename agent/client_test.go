@@ -0,0 +1,128 @@
+package agent_test
+
+import (
+	. "."
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+// Starts local stand-in for agent. If json is true, it understands JSON passive checks protocol.
+// If header is false, it responds without header like old agents.
+func serveAgent(t *testing.T, values map[string]string, jsonProtocol, header bool) (addr string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer l.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := protocol.Read(bufio.NewReader(conn), protocol.MaxDataSize)
+			if err != nil {
+				t.Error(err)
+				conn.Close()
+				continue
+			}
+
+			key := string(b)
+			var response string
+			if jsonProtocol && strings.HasPrefix(key, "{") {
+				var req struct {
+					Data []struct {
+						Key     string `json:"key"`
+						Timeout string `json:"timeout"`
+					} `json:"data"`
+				}
+				json.Unmarshal(b, &req)
+				value, ok := values[req.Data[0].Key]
+				data := map[string]string{"value": value}
+				if !ok {
+					data = map[string]string{"error": "Unsupported item key."}
+				}
+				if req.Data[0].Timeout == "0s" {
+					data = map[string]string{"error": "Invalid timeout."}
+				}
+				res, _ := json.Marshal(map[string]interface{}{"version": "7.0.0", "variant": 2, "data": []interface{}{data}})
+				response = string(res)
+			} else if value, ok := values[key]; ok {
+				response = value
+			} else {
+				response = "ZBX_NOTSUPPORTED\x00Unsupported item key."
+			}
+
+			if header {
+				protocol.Write(conn, []byte(response), false)
+			} else {
+				conn.Write([]byte(response + "\n"))
+			}
+			conn.Close()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestGet(t *testing.T) {
+	values := map[string]string{"agent.ping": "1", "system.uname": "Linux host"}
+
+	for _, jsonProtocol := range []bool{false, true} {
+		for _, header := range []bool{false, true} {
+			addr := serveAgent(t, values, jsonProtocol, header)
+			for _, c := range []*Client{{Timeout: time.Second}, {Timeout: time.Second, JSON: true}} {
+				value, err := c.Get(addr, "system.uname")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if value != "Linux host" {
+					t.Errorf("Bad value: %q", value)
+				}
+
+				_, err = c.Get(addr, "no.such.key")
+				e, ok := err.(*NotSupported)
+				if !ok || e.Message != "Unsupported item key." {
+					t.Errorf("Expected NotSupported, got %#v", err)
+				}
+			}
+		}
+	}
+}
+
+func TestGetShortTimeout(t *testing.T) {
+	addr := serveAgent(t, map[string]string{"agent.ping": "1"}, true, true)
+	value, err := (&Client{Timeout: 500 * time.Millisecond, JSON: true}).Get(addr, "agent.ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "1" {
+		t.Errorf("Bad value: %q", value)
+	}
+}
+
+func TestGetFromInterface(t *testing.T) {
+	addr := serveAgent(t, map[string]string{"agent.ping": "1"}, false, true)
+	host, port, _ := net.SplitHostPort(addr)
+
+	iface := zabbix.HostInterface{IP: host, Port: port, UseIP: 1, Type: zabbix.Agent}
+	value, err := NewClient().GetFromInterface(iface, "agent.ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "1" {
+		t.Errorf("Bad value: %q", value)
+	}
+
+	iface.Type = zabbix.SNMP
+	_, err = NewClient().GetFromInterface(iface, "agent.ping")
+	if _, ok := err.(WrongInterfaceType); !ok {
+		t.Errorf("Expected WrongInterfaceType, got %#v", err)
+	}
+}
@@ -0,0 +1,7 @@
+// Package agent implements Zabbix agent protocols: querying agents with passive checks
+// like zabbix_get does, serving passive checks from Go handlers, so items of type
+// zabbix.ZabbixAgent may target Go processes instead of zabbix_agentd, and running
+// active checks (zabbix.ZabbixAgentActive items) with the same handlers.
+//
+// See https://www.zabbix.com/documentation/current/manual/appendix/items/activepassive
+package agent
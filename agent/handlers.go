@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Message returned for keys without handler, as in Zabbix agent.
+const UnsupportedKey = "Unsupported item key."
+
+// Gets item value for key parameters. Returned value is converted to string
+// (numbers in decimal notation, booleans as 1 and 0, durations in seconds);
+// returned error message is sent as ZBX_NOTSUPPORTED message.
+type HandlerFunc func(params []string) (value interface{}, err error)
+
+// Registry of handlers by key name.
+type Handlers struct {
+	rw       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// Registers handler for key name (without parameters like "app.requests").
+func (h *Handlers) Handle(name string, handler HandlerFunc) {
+	h.rw.Lock()
+	defer h.rw.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[string]HandlerFunc)
+	}
+	h.handlers[name] = handler
+}
+
+// Gets value of item key with registered handler.
+// Returns *NotSupported error if key is invalid, has no handler or handler fails.
+func (h *Handlers) Get(key string) (value string, err error) {
+	name, params, err := ParseKey(key)
+	if err != nil {
+		err = &NotSupported{key, "Invalid item key format."}
+		return
+	}
+
+	h.rw.RLock()
+	handler := h.handlers[name]
+	h.rw.RUnlock()
+	if handler == nil {
+		err = &NotSupported{key, UnsupportedKey}
+		return
+	}
+
+	v, err := handler(params)
+	if err != nil {
+		err = &NotSupported{key, err.Error()}
+		return
+	}
+	value = FormatValue(v)
+	return
+}
+
+// Converts handler value to string sent to Zabbix.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Duration:
+		return strconv.FormatFloat(v.Seconds(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+type InvalidKey string
+
+func (e InvalidKey) Error() string {
+	return fmt.Sprintf("Invalid item key format: %s", string(e))
+}
+
+// Splits item key like `vfs.fs.size[/,free]` into name and parameters.
+// Quoted parameters are unquoted, array parameters are returned without brackets.
+func ParseKey(key string) (name string, params []string, err error) {
+	i := strings.IndexByte(key, '[')
+	if i < 0 {
+		name = key
+	} else {
+		name = key[:i]
+	}
+	if name == "" {
+		err = InvalidKey(key)
+		return
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			err = InvalidKey(key)
+			return
+		}
+	}
+	if i < 0 {
+		return
+	}
+
+	if !strings.HasSuffix(key, "]") {
+		err = InvalidKey(key)
+		return
+	}
+	params, ok := parseParams(key[i+1 : len(key)-1])
+	if !ok {
+		err = InvalidKey(key)
+	}
+	return
+}
+
+func parseParams(s string) (params []string, ok bool) {
+	pos := 0
+	skipSpaces := func() {
+		for pos < len(s) && s[pos] == ' ' {
+			pos++
+		}
+	}
+
+	for {
+		skipSpaces()
+		var param string
+		switch {
+		case pos < len(s) && s[pos] == '"':
+			end, unquoted, found := quotedEnd(s, pos)
+			if !found {
+				return
+			}
+			param, pos = unquoted, end+1
+			skipSpaces()
+
+		case pos < len(s) && s[pos] == '[':
+			end := pos + 1
+			for end < len(s) && s[end] != ']' {
+				if s[end] == '"' {
+					var found bool
+					if end, _, found = quotedEnd(s, end); !found {
+						return
+					}
+				}
+				end++
+			}
+			if end >= len(s) {
+				return
+			}
+			param, pos = s[pos+1:end], end+1
+			skipSpaces()
+
+		default:
+			end := strings.IndexAny(s[pos:], ",]")
+			if end < 0 {
+				end = len(s) - pos
+			} else if s[pos+end] == ']' {
+				return
+			}
+			param, pos = s[pos:pos+end], pos+end
+		}
+
+		params = append(params, param)
+		if pos >= len(s) {
+			ok = true
+			return
+		}
+		if s[pos] != ',' {
+			return
+		}
+		pos++
+	}
+}
+
+// Returns position of closing quote for quoted string starting at start and unquoted string.
+func quotedEnd(s string, start int) (end int, unquoted string, found bool) {
+	var b strings.Builder
+	for end = start + 1; end < len(s); end++ {
+		switch {
+		case s[end] == '\\' && end+1 < len(s) && s[end+1] == '"':
+			b.WriteByte('"')
+			end++
+		case s[end] == '"':
+			return end, b.String(), true
+		default:
+			b.WriteByte(s[end])
+		}
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package agent_test
+
+import (
+	. "."
+	"reflect"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	for key, expected := range map[string][]string{
+		"agent.ping":                     {"agent.ping"},
+		"vfs.fs.size[/,free]":            {"vfs.fs.size", "/", "free"},
+		"key[]":                          {"key", ""},
+		`key["a,b", "c\"d" ,e]`:          {"key", "a,b", `c"d`, "e"},
+		`net.tcp.service[ssh,,22]`:       {"net.tcp.service", "ssh", "", "22"},
+		`web.page.regexp[a,[b,"c]"],d]`:  {"web.page.regexp", "a", `b,"c]"`, "d"},
+		`proc.num[,,,"/usr/bin/app -x"]`: {"proc.num", "", "", "", "/usr/bin/app -x"},
+	} {
+		name, params, err := ParseKey(key)
+		if err != nil {
+			t.Errorf("%s: %s", key, err)
+			continue
+		}
+		if name != expected[0] || len(params) > 0 && !reflect.DeepEqual(params, expected[1:]) || len(params)+1 != len(expected) {
+			t.Errorf("%s: got %q %q", key, name, params)
+		}
+	}
+
+	for _, key := range []string{"", "[a]", "key[a", `key["a]`, "key[a]b]", "ke y", `key["a"b]`} {
+		if _, _, err := ParseKey(key); err == nil {
+			t.Errorf("%s: expected error", key)
+		}
+	}
+}
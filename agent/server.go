@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+// Maximum passive check request size.
+const maxRequestSize = 64 * 1024
+
+// Passive checks server, replacement for zabbix_agentd in Go processes.
+type Server struct {
+	Handlers
+
+	// IP addresses, CIDR networks or host names allowed to connect, like Server option of agent.
+	// Only loopback addresses are allowed if empty.
+	AllowedServers []string
+
+	Timeout time.Duration // for reading request and writing response
+	Logger  *log.Logger   // nil by default
+
+	m        sync.Mutex
+	listener net.Listener
+}
+
+// Creates new server with default timeout accepting connections from given servers.
+func NewServer(allowedServers ...string) *Server {
+	return &Server{AllowedServers: allowedServers, Timeout: DefaultTimeout}
+}
+
+func (s *Server) printf(format string, v ...interface{}) {
+	if s.Logger != nil {
+		s.Logger.Printf(format, v...)
+	}
+}
+
+// Listens on TCP address addr (like ":10050") and serves requests.
+func (s *Server) ListenAndServe(addr string) (err error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return
+	}
+	return s.Serve(l)
+}
+
+// Accepts connections on l and serves each in a new goroutine until l is closed.
+func (s *Server) Serve(l net.Listener) (err error) {
+	s.m.Lock()
+	s.listener = l
+	s.m.Unlock()
+
+	for {
+		var conn net.Conn
+		conn, err = l.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Stops accepting connections.
+func (s *Server) Close() (err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	return
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	if !s.allowed(net.ParseIP(host)) {
+		s.printf("Connection from %s rejected", host)
+		return
+	}
+
+	if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+	key, err := readRequest(conn)
+	if err != nil {
+		s.printf("Failed to read request from %s: %s", host, err)
+		return
+	}
+
+	value, err := s.get(host, key)
+	if err != nil {
+		value = notSupported + "\x00" + err.(*NotSupported).Message
+	}
+	s.printf("Request from %s: %s, response: %q", host, key, value)
+
+	if err = protocol.Write(conn, []byte(value), false); err != nil {
+		s.printf("Failed to write response to %s: %s", host, err)
+	}
+}
+
+// Gets value like Handlers.Get, but reports panic of handler as not supported item,
+// so it affects only the current connection.
+func (s *Server) get(host, key string) (value string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.printf("Handler of %s requested by %s panicked: %v", key, host, r)
+			value, err = "", &NotSupported{key, fmt.Sprintf("Handler panicked: %v.", r)}
+		}
+	}()
+	return s.Get(key)
+}
+
+// Reads key with header or, from old servers, plain text line.
+// Line may be shorter than header and may end with closed connection instead of newline.
+func readRequest(conn net.Conn) (key string, err error) {
+	r := bufio.NewReader(conn)
+	if _, err = r.Peek(1); err != nil {
+		return
+	}
+	head, _ := r.Peek(r.Buffered())
+	if len(head) > len(protocol.Header) {
+		head = head[:len(protocol.Header)]
+	}
+	if len(head) < len(protocol.Header) && bytes.HasPrefix(protocol.Header, head) {
+		// rest of header is not received yet; fewer bytes are returned for short plain text lines
+		head, _ = r.Peek(len(protocol.Header))
+	}
+
+	var b []byte
+	if bytes.Equal(head, protocol.Header) {
+		b, err = protocol.Read(r, maxRequestSize)
+	} else {
+		b, err = r.ReadSlice('\n')
+		if err == io.EOF && len(b) > 0 {
+			err = nil
+		}
+	}
+	key = strings.TrimRight(string(b), "\r\n")
+	return
+}
+
+func (s *Server) allowed(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if len(s.AllowedServers) == 0 {
+		return ip.IsLoopback()
+	}
+
+	for _, a := range s.AllowedServers {
+		a = strings.TrimSpace(a)
+		if _, network, err := net.ParseCIDR(a); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(a); allowed != nil {
+			if allowed.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		addrs, err := net.LookupHost(a)
+		if err != nil {
+			s.printf("Failed to resolve %s: %s", a, err)
+			continue
+		}
+		for _, addr := range addrs {
+			if net.ParseIP(addr).Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
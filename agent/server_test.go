@@ -0,0 +1,117 @@
+package agent_test
+
+import (
+	. "."
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+func startServer(t *testing.T, allowed ...string) (s *Server, addr string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = NewServer(allowed...)
+	s.Handle("app.requests", func(params []string) (interface{}, error) {
+		return 42, nil
+	})
+	s.Handle("app.ratio", func(params []string) (interface{}, error) {
+		if len(params) != 1 || params[0] == "" {
+			return nil, errors.New("Invalid first parameter.")
+		}
+		return 0.5, nil
+	})
+	s.Handle("app.panic", func(params []string) (interface{}, error) {
+		panic("lala")
+	})
+	go s.Serve(l)
+	return s, l.Addr().String()
+}
+
+func TestServer(t *testing.T) {
+	s, addr := startServer(t)
+	defer s.Close()
+
+	c := &Client{Timeout: time.Second}
+	for key, expected := range map[string]string{"app.requests": "42", "app.ratio[cache]": "0.5"} {
+		value, err := c.Get(addr, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, value)
+		}
+	}
+
+	for key, expected := range map[string]string{
+		"app.ratio[]":   "Invalid first parameter.",
+		"app.missing":   UnsupportedKey,
+		"app.ratio[a]]": "Invalid item key format.",
+		"app.panic":     "Handler panicked: lala.",
+	} {
+		_, err := c.Get(addr, key)
+		e, ok := err.(*NotSupported)
+		if !ok || e.Message != expected {
+			t.Errorf("%s: expected NotSupported %q, got %#v", key, expected, err)
+		}
+	}
+
+	// server is still running after handler panic
+	if value, err := c.Get(addr, "app.requests"); err != nil || value != "42" {
+		t.Errorf("Unexpected value %q, error %v", value, err)
+	}
+}
+
+func TestServerPlainText(t *testing.T) {
+	s, addr := startServer(t)
+	defer s.Close()
+
+	for request, expected := range map[string]string{
+		"a\n":          "ZBX_NOTSUPPORTED\x00" + UnsupportedKey,
+		"app.requests": "42",
+	} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.SetDeadline(time.Now().Add(time.Second))
+		if _, err = conn.Write([]byte(request)); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(request, "\n") {
+			conn.(*net.TCPConn).CloseWrite()
+		}
+		b, err := protocol.Read(conn, protocol.MaxDataSize)
+		conn.Close()
+		if err != nil {
+			t.Errorf("%q: %s", request, err)
+			continue
+		}
+		if string(b) != expected {
+			t.Errorf("%q: expected %q, got %q", request, expected, b)
+		}
+	}
+}
+
+func TestServerAllowed(t *testing.T) {
+	s, addr := startServer(t, "10.0.0.1", "192.168.0.0/16")
+	defer s.Close()
+
+	_, err := (&Client{Timeout: time.Second}).Get(addr, "app.requests")
+	if err == nil {
+		t.Error("Expected error")
+	}
+
+	s2, addr2 := startServer(t, "10.0.0.1", "127.0.0.0/8")
+	defer s2.Close()
+
+	_, err = (&Client{Timeout: time.Second}).Get(addr2, "app.requests")
+	if err != nil {
+		t.Error(err)
+	}
+}
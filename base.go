@@ -7,7 +7,12 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync/atomic"
+
+	"github.com/AlekSi/reflector"
 )
 
 type (
@@ -152,3 +157,165 @@ func (api *API) Version() (v string, err error) {
 	v = response.Result.(string)
 	return
 }
+
+// Converts API result (object or array of objects) to struct, pointer to struct or slice pointed by ptr.
+// Unlike plain reflector call, also converts nested objects and arrays (returned for select* options)
+// to fields of struct, pointer to struct and slice types.
+// Empty arrays, which API returns in place of empty objects, are ignored.
+func decodeResult(result interface{}, ptr interface{}) {
+	decodeValue(result, reflect.ValueOf(ptr).Elem())
+}
+
+func decodeValue(value interface{}, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Slice:
+		values, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, e := range values {
+			decodeValue(e, s.Index(i))
+		}
+		v.Set(s)
+
+	case reflect.Ptr:
+		// pointers to structs are set for objects only (API returns empty array in place of empty object),
+		// pointers to scalars for string and number values
+		_, object := value.(map[string]interface{})
+		_, str := value.(string)
+		_, number := value.(float64)
+		if object || ((str || number) && v.Type().Elem().Kind() != reflect.Struct) {
+			p := reflect.New(v.Type().Elem())
+			decodeValue(value, p.Elem())
+			v.Set(p)
+		}
+
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		// reflector converts only scalar fields, nested ones are converted recursively
+		scalars := make(map[string]interface{}, len(m))
+		nested := make(map[int]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			fv, present := m[name]
+			if name == "" || name == "-" || !present {
+				continue
+			}
+			switch t.Field(i).Type.Kind() {
+			case reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Map:
+				nested[i] = fv
+			default:
+				scalars[name] = fv
+			}
+		}
+
+		s := reflect.New(reflect.SliceOf(t))
+		reflector.MapsToStructs2([]interface{}{scalars}, s.Interface(), reflector.Strconv, "json")
+		if s.Elem().Len() == 1 {
+			v.Set(s.Elem().Index(0))
+		}
+		for i, fv := range nested {
+			decodeValue(fv, v.Field(i))
+		}
+
+	case reflect.Map:
+		if m, ok := value.(map[string]interface{}); ok && v.Type().Key().Kind() == reflect.String {
+			mv := reflect.MakeMap(v.Type())
+			for k, e := range m {
+				ev := reflect.New(v.Type().Elem()).Elem()
+				decodeValue(e, ev)
+				mv.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), ev)
+			}
+			v.Set(mv)
+		}
+
+	case reflect.Interface:
+		if value != nil {
+			v.Set(reflect.ValueOf(value))
+		}
+
+	default:
+		// scalar elements of slices, like []string
+		decodeScalar(value, v)
+	}
+}
+
+func decodeScalar(value interface{}, v reflect.Value) {
+	s, ok := value.(string)
+	if !ok {
+		if f, isFloat := value.(float64); isFloat {
+			s, ok = strconv.FormatFloat(f, 'f', -1, 64), true
+		}
+	}
+	if !ok {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.ParseUint(s, 10, 64); err == nil {
+			v.SetUint(i)
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			v.SetFloat(f)
+		}
+	}
+}
+
+// Returns parameters for update method of object with given id field.
+// Only given fields (named as in JSON) are returned along with id, even if they are empty and marked with omitempty;
+// all fields except read-only ones are returned if none given. Unknown field names are reported as error.
+func updateParams(obj interface{}, idField string, readOnly, fields []string) (params Params, err error) {
+	if len(fields) == 0 {
+		var b []byte
+		b, err = json.Marshal(obj)
+		if err != nil {
+			return
+		}
+		params = make(Params)
+		err = json.Unmarshal(b, &params)
+		for _, f := range readOnly {
+			delete(params, f)
+		}
+		return
+	}
+
+	all := jsonFields(obj)
+	params = Params{idField: all[idField]}
+	for _, f := range fields {
+		v, present := all[f]
+		if !present {
+			return nil, fmt.Errorf("Unknown field %q.", f)
+		}
+		params[f] = v
+	}
+	return
+}
+
+// Returns values of struct fields keyed by their JSON names, including empty ones marked with omitempty.
+func jsonFields(s interface{}) (fields Params) {
+	v := reflect.ValueOf(s)
+	t := v.Type()
+	fields = make(Params, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = v.Field(i).Interface()
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package zabbix
+
+import (
+	"github.com/AlekSi/zabbix/internal/apiutil"
+)
+
+type ExportFormat string
+
+const (
+	ExportXML  ExportFormat = "xml"
+	ExportJSON ExportFormat = "json"
+	ExportYAML ExportFormat = "yaml" // Zabbix 5.2+
+)
+
+// Ids of objects to export: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/export
+// Available object types depend on Zabbix version.
+type ExportOptions struct {
+	Groups         []string `json:"groups,omitempty"` // host groups before Zabbix 6.2
+	HostGroups     []string `json:"host_groups,omitempty"`
+	TemplateGroups []string `json:"template_groups,omitempty"`
+	Hosts          []string `json:"hosts,omitempty"`
+	Images         []string `json:"images,omitempty"`
+	Maps           []string `json:"maps,omitempty"`
+	MediaTypes     []string `json:"mediaTypes,omitempty"`
+	Screens        []string `json:"screens,omitempty"`
+	Templates      []string `json:"templates,omitempty"`
+	ValueMaps      []string `json:"valueMaps,omitempty"`
+}
+
+// Import rule for single object type. Not all object types support all three flags,
+// so unset flags are not sent.
+type ImportRule struct {
+	CreateMissing  bool `json:"createMissing,omitempty"`
+	UpdateExisting bool `json:"updateExisting,omitempty"`
+	DeleteMissing  bool `json:"deleteMissing,omitempty"`
+}
+
+// Import rules: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/import
+// Object types with nil rule are not imported. Available object types depend on Zabbix version.
+type ImportRules struct {
+	Applications       *ImportRule `json:"applications,omitempty"`
+	DiscoveryRules     *ImportRule `json:"discoveryRules,omitempty"`
+	Graphs             *ImportRule `json:"graphs,omitempty"`
+	Groups             *ImportRule `json:"groups,omitempty"` // host groups before Zabbix 6.2
+	HostGroups         *ImportRule `json:"host_groups,omitempty"`
+	TemplateGroups     *ImportRule `json:"template_groups,omitempty"`
+	Hosts              *ImportRule `json:"hosts,omitempty"`
+	HttpTests          *ImportRule `json:"httptests,omitempty"`
+	Images             *ImportRule `json:"images,omitempty"`
+	Items              *ImportRule `json:"items,omitempty"`
+	Maps               *ImportRule `json:"maps,omitempty"`
+	MediaTypes         *ImportRule `json:"mediaTypes,omitempty"`
+	Screens            *ImportRule `json:"screens,omitempty"`
+	TemplateLinkage    *ImportRule `json:"templateLinkage,omitempty"`
+	Templates          *ImportRule `json:"templates,omitempty"`
+	TemplateDashboards *ImportRule `json:"templateDashboards,omitempty"`
+	TemplateScreens    *ImportRule `json:"templateScreens,omitempty"`
+	Triggers           *ImportRule `json:"triggers,omitempty"`
+	ValueMaps          *ImportRule `json:"valueMaps,omitempty"`
+}
+
+// Changes of one object type reported by configuration.importcompare.
+type ImportChanges struct {
+	Added   []map[string]interface{}
+	Removed []map[string]interface{}
+	Updated []ImportUpdate
+}
+
+// Updated object with changes of its nested objects (items of template, for example) by object type.
+type ImportUpdate struct {
+	Before  map[string]interface{}
+	After   map[string]interface{}
+	Changes map[string]*ImportChanges
+}
+
+// Wrapper for configuration.export: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/export
+// Returns exported document as is.
+func (api *API) ConfigurationExport(format ExportFormat, options ExportOptions) (res string, err error) {
+	response, err := api.CallWithError("configuration.export", Params{"format": format, "options": options})
+	if err != nil {
+		return
+	}
+
+	res = response.Result.(string)
+	return
+}
+
+// Wrapper for configuration.import: https://www.zabbix.com/documentation/4.0/manual/api/reference/configuration/import
+func (api *API) ConfigurationImport(format ExportFormat, source string, rules ImportRules) (err error) {
+	_, err = api.CallWithError("configuration.import", Params{"format": format, "source": source, "rules": rules})
+	return
+}
+
+// Wrapper for configuration.importcompare: https://www.zabbix.com/documentation/5.4/manual/api/reference/configuration/importcompare
+// Returns changes which import with the same arguments would make, by object type. Requires Zabbix 5.4+.
+func (api *API) ConfigurationImportCompare(format ExportFormat, source string, rules ImportRules) (res map[string]*ImportChanges, err error) {
+	response, err := api.CallWithError("configuration.importcompare", Params{"format": format, "source": source, "rules": rules})
+	if err != nil {
+		return
+	}
+
+	res = decodeImportChanges(response.Result)
+	return
+}
+
+// Decodes importcompare result; API returns empty array in place of empty object.
+func decodeImportChanges(result interface{}) (res map[string]*ImportChanges) {
+	res = make(map[string]*ImportChanges)
+	m, _ := result.(map[string]interface{})
+	for objectType, v := range m {
+		c, _ := v.(map[string]interface{})
+		changes := new(ImportChanges)
+		changes.Added = apiutil.Objects(c["added"])
+		changes.Removed = apiutil.Objects(c["removed"])
+		for _, u := range apiutil.Objects(c["updated"]) {
+			var update ImportUpdate
+			update.Before, _ = u["before"].(map[string]interface{})
+			update.After, _ = u["after"].(map[string]interface{})
+			delete(u, "before")
+			delete(u, "after")
+			update.Changes = decodeImportChanges(u)
+			changes.Updated = append(changes.Updated, update)
+		}
+		res[objectType] = changes
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package zabbix_test
+
+import (
+	. "."
+	"strings"
+	"testing"
+)
+
+func TestConfiguration(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	source, err := api.ConfigurationExport(ExportJSON, ExportOptions{Hosts: []string{host.HostId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(source, host.Host) {
+		t.Fatalf("Host is not exported: %s", source)
+	}
+
+	renamed := strings.Replace(source, host.Name, host.Name+" renamed", -1)
+	rules := ImportRules{Hosts: &ImportRule{UpdateExisting: true}}
+
+	err = api.ConfigurationImport(ExportJSON, renamed, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host2, err := api.HostGetById(host.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host2.Name != host.Name+" renamed" {
+		t.Errorf("Host is not updated: %#v", host2)
+	}
+}
+
+func TestConfigurationImportCompare(t *testing.T) {
+	requireVersion(t, 5, 4)
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	source, err := api.ConfigurationExport(ExportYAML, ExportOptions{Hosts: []string{host.HostId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := strings.Replace(source, host.Name, host.Name+" renamed", -1)
+	changes, err := api.ConfigurationImportCompare(ExportYAML, renamed, ImportRules{Hosts: &ImportRule{UpdateExisting: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := changes["hosts"]
+	if hosts == nil || len(hosts.Updated) != 1 || hosts.Updated[0].After["name"] != host.Name+" renamed" {
+		t.Errorf("Bad changes: %#v", changes)
+	}
+}
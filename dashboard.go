@@ -0,0 +1,219 @@
+package zabbix
+
+import (
+	"encoding/json"
+)
+
+type (
+	WidgetFieldType int
+)
+
+const (
+	WidgetFieldInteger        WidgetFieldType = 0
+	WidgetFieldString         WidgetFieldType = 1
+	WidgetFieldHostGroup      WidgetFieldType = 2
+	WidgetFieldHost           WidgetFieldType = 3
+	WidgetFieldItem           WidgetFieldType = 4
+	WidgetFieldItemPrototype  WidgetFieldType = 5
+	WidgetFieldGraph          WidgetFieldType = 6
+	WidgetFieldGraphPrototype WidgetFieldType = 7
+	WidgetFieldMap            WidgetFieldType = 8
+)
+
+// Widget types.
+const (
+	WidgetActionLog       = "actionlog"
+	WidgetClock           = "clock"
+	WidgetDataOverview    = "dataover"
+	WidgetDiscovery       = "discovery"
+	WidgetFavGraphs       = "favgraphs"
+	WidgetFavMaps         = "favmaps"
+	WidgetFavScreens      = "favscreens"
+	WidgetGraph           = "graph"
+	WidgetSvgGraph        = "svggraph"
+	WidgetGraphPrototype  = "graphprototype"
+	WidgetHostAvail       = "hostavail"
+	WidgetMap             = "map"
+	WidgetNavTree         = "navtree"
+	WidgetPlainText       = "plaintext"
+	WidgetProblemHosts    = "problemhosts"
+	WidgetProblems        = "problems"
+	WidgetProblemsBySv    = "problemsbysv"
+	WidgetSystemInfo      = "systeminfo"
+	WidgetTriggerOverview = "trigover"
+	WidgetURL             = "url"
+	WidgetWeb             = "web"
+)
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/object
+type Dashboard struct {
+	DashboardId string           `json:"dashboardid,omitempty"`
+	Name        string           `json:"name"`
+	UserId      string           `json:"userid,omitempty"`
+	Private     *int             `json:"private,omitempty"` // 0 - public, 1 - private; server default 1 is used if nil
+	Widgets     DashboardWidgets `json:"widgets,omitempty"`
+}
+
+// Sends Widgets even if there are none unless they are nil, so all widgets can be removed on update.
+func (d Dashboard) MarshalJSON() ([]byte, error) {
+	type plain Dashboard
+	if d.Widgets == nil || len(d.Widgets) > 0 {
+		return json.Marshal(plain(d))
+	}
+	return json.Marshal(struct {
+		plain
+		Widgets DashboardWidgets `json:"widgets"`
+	}{plain(d), d.Widgets})
+}
+
+type Dashboards []Dashboard
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/object#dashboard_widget
+// Position and size are in dashboard grid cells: 12 columns of 70 pixel high rows.
+type DashboardWidget struct {
+	WidgetId string                `json:"widgetid,omitempty"`
+	Type     string                `json:"type"`
+	Name     string                `json:"name,omitempty"`
+	X        int                   `json:"x"`
+	Y        int                   `json:"y"`
+	Width    int                   `json:"width"`
+	Height   int                   `json:"height"`
+	ViewMode int                   `json:"view_mode,omitempty"` // Zabbix 4.2+
+	Fields   DashboardWidgetFields `json:"fields,omitempty"`
+}
+
+type DashboardWidgets []DashboardWidget
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/object#dashboard_widget_field
+// Value is id of referenced object for object field types.
+type DashboardWidgetField struct {
+	Type  WidgetFieldType `json:"type"`
+	Name  string          `json:"name"`
+	Value string          `json:"value"`
+}
+
+type DashboardWidgetFields []DashboardWidgetField
+
+// Returns value of widget field with given name.
+func (w *DashboardWidget) Field(name string) (value string, found bool) {
+	for _, f := range w.Fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return
+}
+
+// Wrapper for dashboard.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/get
+// Widgets are requested too.
+func (api *API) DashboardsGet(params Params) (res Dashboards, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectWidgets"]; !present {
+		params["selectWidgets"] = "extend"
+	}
+	response, err := api.CallWithError("dashboard.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets dashboard by Id only if there is exactly 1 matching dashboard.
+func (api *API) DashboardGetById(id string) (res *Dashboard, err error) {
+	dashboards, err := api.DashboardsGet(Params{"dashboardids": id})
+	if err != nil {
+		return
+	}
+
+	if len(dashboards) == 1 {
+		res = &dashboards[0]
+	} else {
+		e := ExpectedOneResult(len(dashboards))
+		err = &e
+	}
+	return
+}
+
+// Gets dashboard by name only if there is exactly 1 matching dashboard. Dashboards are filtered by server.
+func (api *API) DashboardGetByName(name string) (res *Dashboard, err error) {
+	dashboards, err := api.DashboardsGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(dashboards) == 1 {
+		res = &dashboards[0]
+	} else {
+		e := ExpectedOneResult(len(dashboards))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for dashboard.create: https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/create
+// Fills DashboardId of dashboards, but not WidgetId of widgets.
+func (api *API) DashboardsCreate(dashboards Dashboards) (err error) {
+	response, err := api.CallWithError("dashboard.create", dashboards)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	dashboardids := result["dashboardids"].([]interface{})
+	for i, id := range dashboardids {
+		dashboards[i].DashboardId = id.(string)
+	}
+	return
+}
+
+// Wrapper for dashboard.update: https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/update
+// If Widgets are not nil, they replace all existing widgets; widgets without WidgetId are created.
+func (api *API) DashboardsUpdate(dashboards Dashboards) (err error) {
+	response, err := api.CallWithError("dashboard.update", dashboards)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	dashboardids := result["dashboardids"].([]interface{})
+	if len(dashboards) != len(dashboardids) {
+		err = &ExpectedMore{len(dashboards), len(dashboardids)}
+	}
+	return
+}
+
+// Wrapper for dashboard.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/delete
+// Cleans DashboardId in all dashboards elements if call succeed.
+func (api *API) DashboardsDelete(dashboards Dashboards) (err error) {
+	ids := make([]string, len(dashboards))
+	for i, dashboard := range dashboards {
+		ids[i] = dashboard.DashboardId
+	}
+
+	err = api.DashboardsDeleteByIds(ids)
+	if err == nil {
+		for i := range dashboards {
+			dashboards[i].DashboardId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for dashboard.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/dashboard/delete
+func (api *API) DashboardsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("dashboard.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	dashboardids := result["dashboardids"].([]interface{})
+	if len(ids) != len(dashboardids) {
+		err = &ExpectedMore{len(ids), len(dashboardids)}
+	}
+	return
+}
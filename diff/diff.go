@@ -0,0 +1,210 @@
+// Package diff compares configuration of two Zabbix servers, like staging and production.
+//
+// Host groups, hosts, applications, items and graphs are matched by natural keys
+// (names, technical host names and item keys) instead of ids, which differ between servers:
+//
+//	report, err := diff.Servers(staging, production, diff.Filter{HostGroups: []string{"Linux servers"}})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Print(report) // or json.Marshal(report)
+//
+// Values are compared as returned by servers, so servers of different versions may report
+// formatting differences (like delay 60 and 1m).
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AlekSi/zabbix"
+)
+
+type Kind int
+
+const (
+	KindHostGroup Kind = iota
+	KindHost
+	KindApplication
+	KindItem
+	KindGraph
+)
+
+var kindNames = []string{"host group", "host", "application", "item", "graph"}
+
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *Kind) UnmarshalText(text []byte) error {
+	for i, name := range kindNames {
+		if name == string(text) {
+			*k = Kind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown kind %q", text)
+}
+
+type Action int
+
+const (
+	Added   Action = iota // object exists only on second server
+	Removed               // object exists only on first server
+	Changed               // object exists on both servers with different fields
+)
+
+var actionNames = []string{"added", "removed", "changed"}
+
+func (a Action) String() string {
+	if a >= 0 && int(a) < len(actionNames) {
+		return actionNames[a]
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	for i, name := range actionNames {
+		if name == string(text) {
+			*a = Action(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action %q", text)
+}
+
+// Object with natural key, like "web1/system.cpu.load" for item, and compared fields.
+// Lists in fields (like host groups of host) are sorted and joined with ", ".
+type Object struct {
+	Kind   Kind              `json:"kind"`
+	Key    string            `json:"key"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Configuration of one server. It can be stored as JSON and compared later.
+type Snapshot struct {
+	Objects []Object `json:"objects"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type Change struct {
+	Action Action        `json:"action"`
+	Kind   Kind          `json:"kind"`
+	Key    string        `json:"key"`
+	Fields []FieldChange `json:"fields,omitempty"` // for Changed only
+}
+
+// Differences between two snapshots, ordered by kind and key.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Returns human-readable report: one line per added (+) or removed (-) object,
+// changed (~) objects are followed by indented changed fields.
+func (r *Report) String() string {
+	var s []string
+	for _, c := range r.Changes {
+		sign := map[Action]string{Added: "+", Removed: "-", Changed: "~"}[c.Action]
+		s = append(s, fmt.Sprintf("%s %s %s\n", sign, c.Kind, c.Key))
+		for _, f := range c.Fields {
+			s = append(s, fmt.Sprintf("    %s: %q -> %q\n", f.Field, f.Old, f.New))
+		}
+	}
+	return strings.Join(s, "")
+}
+
+// Returns report as indented JSON.
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Compares snapshots of first (old) and second (new) servers.
+func Compare(a, b *Snapshot) *Report {
+	type id struct {
+		kind Kind
+		key  string
+	}
+	index := func(s *Snapshot) map[id]*Object {
+		m := make(map[id]*Object, len(s.Objects))
+		for i := range s.Objects {
+			o := &s.Objects[i]
+			m[id{o.Kind, o.Key}] = o
+		}
+		return m
+	}
+	oldObjects, newObjects := index(a), index(b)
+
+	report := &Report{Changes: []Change{}}
+	for k, o := range oldObjects {
+		n, found := newObjects[k]
+		if !found {
+			report.Changes = append(report.Changes, Change{Action: Removed, Kind: k.kind, Key: k.key})
+			continue
+		}
+		if fields := compareFields(o.Fields, n.Fields); len(fields) > 0 {
+			report.Changes = append(report.Changes, Change{Action: Changed, Kind: k.kind, Key: k.key, Fields: fields})
+		}
+	}
+	for k := range newObjects {
+		if _, found := oldObjects[k]; !found {
+			report.Changes = append(report.Changes, Change{Action: Added, Kind: k.kind, Key: k.key})
+		}
+	}
+
+	sort.Slice(report.Changes, func(i, j int) bool {
+		ci, cj := report.Changes[i], report.Changes[j]
+		if ci.Kind != cj.Kind {
+			return ci.Kind < cj.Kind
+		}
+		return ci.Key < cj.Key
+	})
+	return report
+}
+
+func compareFields(a, b map[string]string) (res []FieldChange) {
+	names := make(map[string]bool)
+	for f := range a {
+		names[f] = true
+	}
+	for f := range b {
+		names[f] = true
+	}
+	for f := range names {
+		if a[f] != b[f] {
+			res = append(res, FieldChange{f, a[f], b[f]})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Field < res[j].Field })
+	return
+}
+
+// Fetches snapshots of both servers with the same filter and compares them.
+func Servers(a, b *zabbix.API, filter Filter) (report *Report, err error) {
+	sa, err := Fetch(a, filter)
+	if err != nil {
+		return
+	}
+	sb, err := Fetch(b, filter)
+	if err != nil {
+		return
+	}
+	return Compare(sa, sb), nil
+}
@@ -0,0 +1,136 @@
+package diff_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/internal/zabbixtest"
+)
+
+func TestCompare(t *testing.T) {
+	a := &Snapshot{Objects: []Object{
+		{KindHostGroup, "Linux servers", nil},
+		{KindHost, "web1", map[string]string{"name": "Web 1", "status": "0"}},
+		{KindItem, "web1/agent.ping", map[string]string{"delay": "60"}},
+		{KindItem, "web1/system.cpu.load", map[string]string{"delay": "60", "history": "7"}},
+	}}
+	b := &Snapshot{Objects: []Object{
+		{KindHostGroup, "Linux servers", nil},
+		{KindHost, "web1", map[string]string{"name": "Web 1", "status": "0"}},
+		{KindHost, "web2", map[string]string{"name": "Web 2", "status": "0"}},
+		{KindItem, "web1/system.cpu.load", map[string]string{"delay": "30", "history": "7", "units": "%"}},
+	}}
+
+	report := Compare(a, b)
+	expected := `+ host web2
+- item web1/agent.ping
+~ item web1/system.cpu.load
+    delay: "60" -> "30"
+    units: "" -> "%"
+`
+	if s := report.String(); s != expected {
+		t.Errorf("Bad report:\n%s\nexpected:\n%s", s, expected)
+	}
+
+	b2, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err = json.Unmarshal(b2, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, report) {
+		t.Errorf("Bad JSON round trip:\n%s", b2)
+	}
+	if report.Changes[0].Action != Added || report.Changes[2].Fields[0] != (FieldChange{"delay", "60", "30"}) {
+		t.Errorf("Bad report %#v", report)
+	}
+
+	if report = Compare(a, a); report.String() != "" || len(report.Changes) != 0 {
+		t.Errorf("Unexpected changes %#v", report)
+	}
+}
+
+func serverResults(hostId, itemId, delay string) map[string]interface{} {
+	return map[string]interface{}{
+		"APIInfo.version": "5.4.0",
+		"hostgroup.get":   []interface{}{map[string]interface{}{"groupid": "2", "name": "Linux servers"}},
+		"host.get": []interface{}{map[string]interface{}{
+			"hostid": hostId, "host": "web1", "name": "Web 1", "status": "0", "description": "",
+			"groups":          []interface{}{map[string]interface{}{"name": "Linux servers"}},
+			"parentTemplates": []interface{}{},
+			"interfaces":      []interface{}{map[string]interface{}{"type": "1", "ip": "192.0.2.10", "dns": "", "port": "10050", "main": "1", "useip": "1"}},
+		}},
+		"item.get": []interface{}{map[string]interface{}{
+			"itemid": itemId, "hostid": hostId, "key_": "system.cpu.load", "name": "CPU load", "delay": delay, "flags": "0",
+		}},
+		"graph.get": []interface{}{map[string]interface{}{
+			"graphid": "7", "name": "CPU", "width": "900", "height": "200", "graphtype": "0", "flags": "0",
+			"hosts":  []interface{}{map[string]interface{}{"hostid": hostId, "host": "web1"}},
+			"items":  []interface{}{map[string]interface{}{"itemid": itemId, "hostid": hostId, "key_": "system.cpu.load"}},
+			"gitems": []interface{}{map[string]interface{}{"itemid": itemId, "color": "00AA00", "sortorder": "0"}},
+		}},
+	}
+}
+
+func TestServers(t *testing.T) {
+	staging := zabbixtest.Server(t, serverResults("10100", "23000", "1m"))
+	defer staging.Close()
+	production := zabbixtest.Server(t, serverResults("10500", "41000", "30s"))
+	defer production.Close()
+
+	report, err := Servers(zabbix.NewAPI(staging.URL), zabbix.NewAPI(production.URL), Filter{HostGroups: []string{"Linux servers"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{{Changed, KindItem, "web1/system.cpu.load", []FieldChange{{"delay", "1m", "30s"}}}}
+	if !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+
+	snapshot, err := Fetch(zabbix.NewAPI(staging.URL), Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := snapshot.Objects[len(snapshot.Objects)-1]
+	if graph.Kind != KindGraph || graph.Key != "web1/CPU" || graph.Fields["items"] != "web1/system.cpu.load 00AA00" {
+		t.Errorf("Unexpected graph %#v", graph)
+	}
+}
+
+func TestFetchGraphOfSeveralHosts(t *testing.T) {
+	results := serverResults("10100", "23000", "1m")
+	results["graph.get"] = []interface{}{map[string]interface{}{
+		"graphid": "7", "name": "CPU", "width": "900", "height": "200", "graphtype": "0", "flags": "0",
+		"hosts": []interface{}{
+			map[string]interface{}{"hostid": "10100", "host": "web1"},
+			map[string]interface{}{"hostid": "10200", "host": "db1"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"itemid": "23000", "hostid": "10100", "key_": "system.cpu.load"},
+			map[string]interface{}{"itemid": "24000", "hostid": "10200", "key_": "system.cpu.load"},
+		},
+		"gitems": []interface{}{
+			map[string]interface{}{"itemid": "23000", "color": "00AA00", "sortorder": "0"},
+			map[string]interface{}{"itemid": "24000", "color": "3333FF", "sortorder": "1"},
+		},
+	}}
+	server := zabbixtest.Server(t, results)
+	defer server.Close()
+
+	snapshot, err := Fetch(zabbix.NewAPI(server.URL), Filter{Hosts: []string{"web1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := snapshot.Objects[len(snapshot.Objects)-1]
+	if graph.Key != "web1/CPU" || graph.Fields["items"] != "web1/system.cpu.load 00AA00, db1/system.cpu.load 3333FF" {
+		t.Errorf("Unexpected graph %#v", graph)
+	}
+	if graph = snapshot.Objects[len(snapshot.Objects)-2]; graph.Kind == KindGraph {
+		t.Errorf("Graph of not selected host %#v", graph)
+	}
+}
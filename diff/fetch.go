@@ -0,0 +1,197 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/internal/apiutil"
+)
+
+// Limits compared objects by names. Empty filter selects all host groups and hosts.
+type Filter struct {
+	HostGroups []string // names of host groups; hosts are limited to their members
+	Hosts      []string // technical names of hosts
+}
+
+// Fetches host groups, hosts (without templates), their applications, items and graphs.
+// Discovered objects are skipped. Applications are not fetched from Zabbix 5.4+, which has none.
+func Fetch(api *zabbix.API, filter Filter) (snapshot *Snapshot, err error) {
+	version, err := api.Version()
+	if err != nil {
+		return
+	}
+	snapshot = new(Snapshot)
+	add := func(kind Kind, key string, fields map[string]string) {
+		snapshot.Objects = append(snapshot.Objects, Object{kind, key, fields})
+	}
+
+	groupParams := zabbix.Params{"output": []string{"groupid", "name"}}
+	if len(filter.HostGroups) > 0 {
+		groupParams["filter"] = map[string]interface{}{"name": filter.HostGroups}
+	}
+	groups, err := call(api, "hostgroup.get", groupParams)
+	if err != nil {
+		return
+	}
+	var groupIds []string
+	for _, g := range groups {
+		groupIds = append(groupIds, apiutil.Str(g["groupid"]))
+		add(KindHostGroup, apiutil.Str(g["name"]), nil)
+	}
+
+	hostParams := zabbix.Params{
+		"output":                []string{"hostid", "host", "name", "status", "description"},
+		"selectGroups":          []string{"name"},
+		"selectParentTemplates": []string{"host"},
+		"selectInterfaces":      []string{"type", "ip", "dns", "port", "main", "useip"},
+	}
+	if len(filter.HostGroups) > 0 {
+		if len(groupIds) == 0 {
+			return
+		}
+		hostParams["groupids"] = groupIds
+	}
+	if len(filter.Hosts) > 0 {
+		hostParams["filter"] = map[string]interface{}{"host": filter.Hosts}
+	}
+	hosts, err := call(api, "host.get", hostParams)
+	if err != nil {
+		return
+	}
+	var hostIds []string
+	hostNames := make(map[string]string) // technical names by id
+	for _, h := range hosts {
+		hostIds = append(hostIds, apiutil.Str(h["hostid"]))
+		hostNames[apiutil.Str(h["hostid"])] = apiutil.Str(h["host"])
+		var interfaces []string
+		for _, i := range apiutil.Objects(h["interfaces"]) {
+			interfaces = append(interfaces, fmt.Sprintf("type=%s ip=%s dns=%s port=%s main=%s useip=%s",
+				apiutil.Str(i["type"]), apiutil.Str(i["ip"]), apiutil.Str(i["dns"]), apiutil.Str(i["port"]), apiutil.Str(i["main"]), apiutil.Str(i["useip"])))
+		}
+		add(KindHost, apiutil.Str(h["host"]), map[string]string{
+			"name":        apiutil.Str(h["name"]),
+			"status":      apiutil.Str(h["status"]),
+			"description": apiutil.Str(h["description"]),
+			"groups":      list(h["groups"], "name"),
+			"templates":   list(h["parentTemplates"], "host"),
+			"interfaces":  join(interfaces),
+		})
+	}
+	if len(hostIds) == 0 {
+		return
+	}
+
+	applications := apiutil.OlderThan(version, 5, 4)
+	if applications {
+		var apps []map[string]interface{}
+		apps, err = call(api, "application.get", zabbix.Params{"hostids": hostIds, "output": []string{"hostid", "name", "flags"}})
+		if err != nil {
+			return
+		}
+		for _, a := range apps {
+			if apiutil.Str(a["flags"]) != "4" {
+				add(KindApplication, hostNames[apiutil.Str(a["hostid"])]+"/"+apiutil.Str(a["name"]), nil)
+			}
+		}
+	}
+
+	itemParams := zabbix.Params{
+		"hostids": hostIds,
+		"output":  []string{"itemid", "hostid", "name", "key_", "type", "value_type", "delay", "history", "trends", "units", "status", "description", "flags"},
+	}
+	if applications {
+		itemParams["selectApplications"] = []string{"name"}
+	}
+	items, err := call(api, "item.get", itemParams)
+	if err != nil {
+		return
+	}
+	for _, i := range items {
+		if apiutil.Str(i["flags"]) == "4" {
+			continue
+		}
+		fields := map[string]string{}
+		for _, f := range []string{"name", "type", "value_type", "delay", "history", "trends", "units", "status", "description"} {
+			fields[f] = apiutil.Str(i[f])
+		}
+		if applications {
+			fields["applications"] = list(i["applications"], "name")
+		}
+		add(KindItem, hostNames[apiutil.Str(i["hostid"])]+"/"+apiutil.Str(i["key_"]), fields)
+	}
+
+	graphs, err := call(api, "graph.get", zabbix.Params{
+		"hostids":          hostIds,
+		"output":           []string{"graphid", "name", "width", "height", "graphtype", "flags"},
+		"selectHosts":      []string{"hostid", "host"},
+		"selectItems":      []string{"itemid", "hostid", "key_"},
+		"selectGraphItems": []string{"itemid", "color", "sortorder"},
+	})
+	if err != nil {
+		return
+	}
+	for _, g := range graphs {
+		if apiutil.Str(g["flags"]) == "4" {
+			continue
+		}
+		// items may belong to hosts which are not selected, so their names are taken from the graph itself
+		graphHosts := make(map[string]string) // technical names by id
+		for _, h := range apiutil.Objects(g["hosts"]) {
+			graphHosts[apiutil.Str(h["hostid"])] = apiutil.Str(h["host"])
+		}
+		keys := make(map[string]string) // host/key by item id
+		for _, i := range apiutil.Objects(g["items"]) {
+			keys[apiutil.Str(i["itemid"])] = graphHosts[apiutil.Str(i["hostid"])] + "/" + apiutil.Str(i["key_"])
+		}
+		graphItems := apiutil.Objects(g["gitems"])
+		sort.SliceStable(graphItems, func(i, j int) bool {
+			a, _ := strconv.Atoi(apiutil.Str(graphItems[i]["sortorder"]))
+			b, _ := strconv.Atoi(apiutil.Str(graphItems[j]["sortorder"]))
+			return a < b
+		})
+		var gitems []string
+		for _, gi := range graphItems {
+			gitems = append(gitems, keys[apiutil.Str(gi["itemid"])]+" "+apiutil.Str(gi["color"]))
+		}
+
+		// graph with items of several hosts is reported once for each of them
+		for _, h := range apiutil.Objects(g["hosts"]) {
+			if _, selected := hostNames[apiutil.Str(h["hostid"])]; !selected {
+				continue
+			}
+			add(KindGraph, apiutil.Str(h["host"])+"/"+apiutil.Str(g["name"]), map[string]string{
+				"width":     apiutil.Str(g["width"]),
+				"height":    apiutil.Str(g["height"]),
+				"graphtype": apiutil.Str(g["graphtype"]),
+				"items":     strings.Join(gitems, ", "),
+			})
+		}
+	}
+	return
+}
+
+func call(api *zabbix.API, method string, params zabbix.Params) (res []map[string]interface{}, err error) {
+	response, err := api.CallWithError(method, params)
+	if err != nil {
+		return
+	}
+	res = apiutil.Objects(response.Result)
+	return
+}
+
+// Returns sorted field values of objects joined with ", ".
+func list(v interface{}, field string) string {
+	var values []string
+	for _, o := range apiutil.Objects(v) {
+		values = append(values, apiutil.Str(o[field]))
+	}
+	return join(values)
+}
+
+func join(values []string) string {
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
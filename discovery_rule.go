@@ -0,0 +1,241 @@
+package zabbix
+
+type (
+	EvalType          int
+	ConditionOperator int
+	OverrideObject    int
+	OverrideOperator  int
+)
+
+const (
+	AndOr  EvalType = 0
+	And    EvalType = 1
+	Or     EvalType = 2
+	Custom EvalType = 3 // uses Formula with condition FormulaIds
+
+	MatchesRegexp    ConditionOperator = 8
+	NotMatchesRegexp ConditionOperator = 9
+	Exists           ConditionOperator = 12 // Zabbix 5.0+
+	NotExists        ConditionOperator = 13 // Zabbix 5.0+
+
+	OverrideItemPrototype    OverrideObject = 0
+	OverrideTriggerPrototype OverrideObject = 1
+	OverrideGraphPrototype   OverrideObject = 2
+	OverrideHostPrototype    OverrideObject = 3
+
+	OverrideEquals      OverrideOperator = 0
+	OverrideNotEquals   OverrideOperator = 1
+	OverrideContains    OverrideOperator = 2
+	OverrideNotContains OverrideOperator = 3
+	OverrideMatches     OverrideOperator = 8
+	OverrideNotMatches  OverrideOperator = 9
+
+	// Related objects which may be requested from DiscoveryRulesGet.
+	SelectFilter        Select = "selectFilter"        // Zabbix 2.4+
+	SelectLLDMacroPaths Select = "selectLLDMacroPaths" // Zabbix 4.2+
+	SelectOverrides     Select = "selectOverrides"     // Zabbix 5.0+
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/discoveryrule/definitions
+// Delay and Lifetime may have suffixes like "1h" and "30d" since Zabbix 3.4.
+type DiscoveryRule struct {
+	ItemId      string     `json:"itemid,omitempty"`
+	HostId      string     `json:"hostid,omitempty"`
+	InterfaceId string     `json:"interfaceid,omitempty"`
+	Key         string     `json:"key_"`
+	Name        string     `json:"name"`
+	Type        ItemType   `json:"type"`
+	Delay       string     `json:"delay"`
+	Lifetime    string     `json:"lifetime,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Status      StatusType `json:"status"`
+	SNMPOID     string     `json:"snmp_oid,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	TemplateId  string     `json:"templateid,omitempty"`
+
+	// Filled by DiscoveryRulesGet when corresponding related objects are selected.
+	Filter     *LLDFilter    `json:"filter,omitempty"`
+	MacroPaths LLDMacroPaths `json:"lld_macro_paths,omitempty"` // Zabbix 4.2+
+	Overrides  LLDOverrides  `json:"overrides,omitempty"`       // Zabbix 5.0+
+}
+
+type DiscoveryRules []DiscoveryRule
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/discoveryrule/object#lld_rule_filter
+type LLDFilter struct {
+	EvalType    EvalType            `json:"evaltype"`
+	Formula     string              `json:"formula,omitempty"`
+	Conditions  LLDFilterConditions `json:"conditions"`
+	EvalFormula string              `json:"eval_formula,omitempty"` // read-only
+}
+
+type LLDFilterCondition struct {
+	Macro     string            `json:"macro"`
+	Value     string            `json:"value"`
+	Operator  ConditionOperator `json:"operator,omitempty"`
+	FormulaId string            `json:"formulaid,omitempty"`
+}
+
+type LLDFilterConditions []LLDFilterCondition
+
+// https://www.zabbix.com/documentation/4.2/manual/api/reference/discoveryrule/object#lld_macro_path
+type LLDMacroPath struct {
+	Macro string `json:"lld_macro"`
+	Path  string `json:"path"`
+}
+
+type LLDMacroPaths []LLDMacroPath
+
+// https://www.zabbix.com/documentation/5.0/manual/api/reference/discoveryrule/object#lld_rule_overrides
+type LLDOverride struct {
+	Name       string                `json:"name"`
+	Step       int                   `json:"step"`
+	Stop       int                   `json:"stop"`
+	Filter     *LLDFilter            `json:"filter,omitempty"`
+	Operations LLDOverrideOperations `json:"operations,omitempty"`
+}
+
+type LLDOverrides []LLDOverride
+
+// Only fields applicable to OperationObject should be set.
+type LLDOverrideOperation struct {
+	OperationObject OverrideObject   `json:"operationobject"`
+	Operator        OverrideOperator `json:"operator"`
+	Value           string           `json:"value"`
+
+	Status    *LLDOpStatus    `json:"opstatus,omitempty"`
+	Discover  *LLDOpDiscover  `json:"opdiscover,omitempty"`
+	Period    *LLDOpPeriod    `json:"opperiod,omitempty"`
+	History   *LLDOpHistory   `json:"ophistory,omitempty"`
+	Trends    *LLDOpTrends    `json:"optrends,omitempty"`
+	Severity  *LLDOpSeverity  `json:"opseverity,omitempty"`
+	Tags      HostTags        `json:"optag,omitempty"`
+	Templates TemplateIds     `json:"optemplate,omitempty"`
+	Inventory *LLDOpInventory `json:"opinventory,omitempty"`
+}
+
+type LLDOverrideOperations []LLDOverrideOperation
+
+type LLDOpStatus struct {
+	Status StatusType `json:"status"`
+}
+
+// Discover is 0 to discover objects and 1 to skip them.
+type LLDOpDiscover struct {
+	Discover int `json:"discover"`
+}
+
+type LLDOpPeriod struct {
+	Delay string `json:"delay"`
+}
+
+type LLDOpHistory struct {
+	History string `json:"history"`
+}
+
+type LLDOpTrends struct {
+	Trends string `json:"trends"`
+}
+
+type LLDOpSeverity struct {
+	Severity int `json:"severity"`
+}
+
+type LLDOpInventory struct {
+	InventoryMode InventoryModeType `json:"inventory_mode"`
+}
+
+// Wrapper for discoveryrule.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/discoveryrule/get
+// Given related objects are requested with "extend" output unless params already contain select option.
+func (api *API) DiscoveryRulesGet(params Params, selects ...Select) (res DiscoveryRules, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	params.addSelects(selects)
+	response, err := api.CallWithError("discoveryrule.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets discovery rules by host Id.
+func (api *API) DiscoveryRulesGetByHostId(id string) (res DiscoveryRules, err error) {
+	return api.DiscoveryRulesGet(Params{"hostids": id})
+}
+
+// Wrapper for discoveryrule.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/discoveryrule/create
+func (api *API) DiscoveryRulesCreate(rules DiscoveryRules) (err error) {
+	response, err := api.CallWithError("discoveryrule.create", rules)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	for i, id := range itemids {
+		rules[i].ItemId = id.(string)
+	}
+	return
+}
+
+// Wrapper for discoveryrule.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/discoveryrule/update
+// HostId, Error and TemplateId are not sent as they can't be changed.
+func (api *API) DiscoveryRulesUpdate(rules DiscoveryRules) (err error) {
+	update := make(DiscoveryRules, len(rules))
+	for i, rule := range rules {
+		rule.HostId, rule.Error, rule.TemplateId = "", "", ""
+		if rule.Filter != nil {
+			filter := *rule.Filter
+			filter.EvalFormula = ""
+			rule.Filter = &filter
+		}
+		update[i] = rule
+	}
+
+	response, err := api.CallWithError("discoveryrule.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	if len(rules) != len(itemids) {
+		err = &ExpectedMore{len(rules), len(itemids)}
+	}
+	return
+}
+
+// Wrapper for discoveryrule.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/discoveryrule/delete
+// Cleans ItemId in all rules elements if call succeed.
+func (api *API) DiscoveryRulesDelete(rules DiscoveryRules) (err error) {
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ItemId
+	}
+
+	err = api.DiscoveryRulesDeleteByIds(ids)
+	if err == nil {
+		for i := range rules {
+			rules[i].ItemId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for discoveryrule.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/discoveryrule/delete
+func (api *API) DiscoveryRulesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("discoveryrule.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	ruleids := result["ruleids"].([]interface{})
+	if len(ids) != len(ruleids) {
+		err = &ExpectedMore{len(ids), len(ruleids)}
+	}
+	return
+}
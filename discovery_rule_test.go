@@ -0,0 +1,117 @@
+package zabbix_test
+
+import (
+	. "."
+	"testing"
+)
+
+func CreateDiscoveryRule(host *Host, t *testing.T) *DiscoveryRule {
+	rules := DiscoveryRules{{
+		HostId:   host.HostId,
+		Key:      "net.if.discovery.lala",
+		Name:     "Interfaces discovery",
+		Type:     ZabbixTrapper,
+		Delay:    "0",
+		Lifetime: "7",
+		Filter: &LLDFilter{
+			EvalType:   And,
+			Conditions: LLDFilterConditions{{Macro: "{#IFNAME}", Value: "^eth", Operator: MatchesRegexp}},
+		},
+	}}
+	err := getAPI(t).DiscoveryRulesCreate(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &rules[0]
+}
+
+func DeleteDiscoveryRule(rule *DiscoveryRule, t *testing.T) {
+	err := getAPI(t).DiscoveryRulesDelete(DiscoveryRules{*rule})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoveryRules(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	rule := CreateDiscoveryRule(host, t)
+	if rule.ItemId == "" {
+		t.Errorf("Id is empty: %#v", rule)
+	}
+
+	rules, err := api.DiscoveryRulesGet(Params{"itemids": rule.ItemId}, SelectFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].Filter == nil || len(rules[0].Filter.Conditions) != 1 || rules[0].Filter.Conditions[0].Macro != "{#IFNAME}" {
+		t.Fatalf("Bad rules: %#v", rules)
+	}
+
+	rules[0].Lifetime = "14"
+	err = api.DiscoveryRulesUpdate(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := ItemPrototypes{{
+		HostId:    host.HostId,
+		RuleId:    rule.ItemId,
+		Key:       "net.if.in.lala[{#IFNAME}]",
+		Name:      "Incoming traffic on {#IFNAME}",
+		Type:      ZabbixTrapper,
+		ValueType: Unsigned,
+		Delay:     "0",
+	}}
+	err = api.ItemPrototypesCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].ItemId == "" {
+		t.Errorf("Id is empty: %#v", items[0])
+	}
+
+	triggers := TriggerPrototypes{{
+		Description: "No traffic on {#IFNAME}",
+		Expression:  "{" + host.Host + ":net.if.in.lala[{#IFNAME}].last()}=0",
+		Priority:    Warning,
+	}}
+	err = api.TriggerPrototypesCreate(triggers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graphs := GraphPrototypes{{Name: "Traffic on {#IFNAME}", Width: 900, Height: 200, GItems: GraphItems{{Itemid: items[0].ItemId, Color: "00AA00"}}}}
+	err = api.GraphPrototypesCreate(graphs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prototypes, err := api.ItemPrototypesGetByRuleId(rule.ItemId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prototypes) != 1 || prototypes[0].Key != items[0].Key {
+		t.Errorf("Bad item prototypes: %#v", prototypes)
+	}
+
+	err = api.GraphPrototypesDelete(graphs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.TriggerPrototypesDelete(triggers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.ItemPrototypesDelete(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	DeleteDiscoveryRule(rule, t)
+}
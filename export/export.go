@@ -0,0 +1,233 @@
+// Package export parses Zabbix configuration export files with templates without server
+// and writes them back in canonical form.
+//
+// XML files of Zabbix 2.0+ and JSON and YAML files of all versions which support them are handled.
+// Model is a superset of all format versions: for example, triggers are exported on top level
+// before Zabbix 5.4 and in items since then, value maps are exported on top level before
+// Zabbix 5.4 and in templates since then. Data is kept as is: enumerations are numbers in
+// old versions and names (like ZABBIX_ACTIVE) in new ones, default values are omitted in new ones.
+// Elements not described by model (like web scenarios and dashboards) are kept in Extra fields
+// of JSON and YAML exports and ExtraElements of XML exports and written back in the same format
+// family, so Parse followed by Canonical doesn't lose them.
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// Root element of export file.
+type Export struct {
+	XMLName        xml.Name   `xml:"zabbix_export" json:"-" yaml:"-"`
+	Version        string     `xml:"version" json:"version" yaml:"version"`
+	Date           string     `xml:"date,omitempty" json:"date,omitempty" yaml:"date,omitempty"`
+	Groups         []Group    `xml:"groups>group,omitempty" json:"groups,omitempty" yaml:"groups,omitempty"`
+	TemplateGroups []Group    `xml:"template_groups>template_group,omitempty" json:"template_groups,omitempty" yaml:"template_groups,omitempty"`
+	Templates      []Template `xml:"templates>template,omitempty" json:"templates,omitempty" yaml:"templates,omitempty"`
+	Triggers       []Trigger  `xml:"triggers>trigger,omitempty" json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	Graphs         []Graph    `xml:"graphs>graph,omitempty" json:"graphs,omitempty" yaml:"graphs,omitempty"`
+	ValueMaps      []ValueMap `xml:"value_maps>value_map,omitempty" json:"value_maps,omitempty" yaml:"value_maps,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type Group struct {
+	UUID string `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Name string `xml:"name" json:"name" yaml:"name"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+// Reference to object by name, like linked template or value map of item.
+type NameRef struct {
+	Name string `xml:"name" json:"name" yaml:"name"`
+}
+
+// Old JSON exports contain empty array in place of missing object.
+func (n *NameRef) UnmarshalJSON(b []byte) error {
+	if string(b) == "[]" {
+		return nil
+	}
+	type plain NameRef
+	return json.Unmarshal(b, (*plain)(n))
+}
+
+type Template struct {
+	UUID           string      `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Template       string      `xml:"template" json:"template" yaml:"template"`
+	Name           string      `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Description    string      `xml:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+	Templates      []NameRef   `xml:"templates>template,omitempty" json:"templates,omitempty" yaml:"templates,omitempty"`
+	Groups         []NameRef   `xml:"groups>group,omitempty" json:"groups,omitempty" yaml:"groups,omitempty"`
+	Applications   []NameRef   `xml:"applications>application,omitempty" json:"applications,omitempty" yaml:"applications,omitempty"`
+	Items          []Item      `xml:"items>item,omitempty" json:"items,omitempty" yaml:"items,omitempty"`
+	DiscoveryRules []Discovery `xml:"discovery_rules>discovery_rule,omitempty" json:"discovery_rules,omitempty" yaml:"discovery_rules,omitempty"`
+	Tags           []Tag       `xml:"tags>tag,omitempty" json:"tags,omitempty" yaml:"tags,omitempty"`
+	Macros         []Macro     `xml:"macros>macro,omitempty" json:"macros,omitempty" yaml:"macros,omitempty"`
+	ValueMaps      []ValueMap  `xml:"valuemaps>valuemap,omitempty" json:"valuemaps,omitempty" yaml:"valuemaps,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type Item struct {
+	UUID          string          `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Name          string          `xml:"name" json:"name" yaml:"name"`
+	Type          string          `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	SNMPOID       string          `xml:"snmp_oid,omitempty" json:"snmp_oid,omitempty" yaml:"snmp_oid,omitempty"`
+	Key           string          `xml:"key" json:"key" yaml:"key"`
+	Delay         string          `xml:"delay,omitempty" json:"delay,omitempty" yaml:"delay,omitempty"`
+	History       string          `xml:"history,omitempty" json:"history,omitempty" yaml:"history,omitempty"`
+	Trends        string          `xml:"trends,omitempty" json:"trends,omitempty" yaml:"trends,omitempty"`
+	Status        string          `xml:"status,omitempty" json:"status,omitempty" yaml:"status,omitempty"`
+	ValueType     string          `xml:"value_type,omitempty" json:"value_type,omitempty" yaml:"value_type,omitempty"`
+	Units         string          `xml:"units,omitempty" json:"units,omitempty" yaml:"units,omitempty"`
+	Params        string          `xml:"params,omitempty" json:"params,omitempty" yaml:"params,omitempty"`
+	Description   string          `xml:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+	Applications  []NameRef       `xml:"applications>application,omitempty" json:"applications,omitempty" yaml:"applications,omitempty"`
+	ValueMap      *NameRef        `xml:"valuemap,omitempty" json:"valuemap,omitempty" yaml:"valuemap,omitempty"`
+	Preprocessing []Preprocessing `xml:"preprocessing>step,omitempty" json:"preprocessing,omitempty" yaml:"preprocessing,omitempty"`
+	Tags          []Tag           `xml:"tags>tag,omitempty" json:"tags,omitempty" yaml:"tags,omitempty"`
+	Triggers      []Trigger       `xml:"triggers>trigger,omitempty" json:"triggers,omitempty" yaml:"triggers,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+// Preprocessing step of item (Zabbix 3.4+). Parameters are exported as one string separated
+// by new lines before Zabbix 5.4 and as list since then.
+type Preprocessing struct {
+	Type               string   `xml:"type" json:"type" yaml:"type"`
+	Params             string   `xml:"params,omitempty" json:"params,omitempty" yaml:"params,omitempty"`
+	Parameters         []string `xml:"parameters>parameter,omitempty" json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	ErrorHandler       string   `xml:"error_handler,omitempty" json:"error_handler,omitempty" yaml:"error_handler,omitempty"`
+	ErrorHandlerParams string   `xml:"error_handler_params,omitempty" json:"error_handler_params,omitempty" yaml:"error_handler_params,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type Trigger struct {
+	UUID               string       `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Expression         string       `xml:"expression" json:"expression" yaml:"expression"`
+	RecoveryMode       string       `xml:"recovery_mode,omitempty" json:"recovery_mode,omitempty" yaml:"recovery_mode,omitempty"`
+	RecoveryExpression string       `xml:"recovery_expression,omitempty" json:"recovery_expression,omitempty" yaml:"recovery_expression,omitempty"`
+	Name               string       `xml:"name" json:"name" yaml:"name"`
+	URL                string       `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Status             string       `xml:"status,omitempty" json:"status,omitempty" yaml:"status,omitempty"`
+	Priority           string       `xml:"priority,omitempty" json:"priority,omitempty" yaml:"priority,omitempty"`
+	Description        string       `xml:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+	ManualClose        string       `xml:"manual_close,omitempty" json:"manual_close,omitempty" yaml:"manual_close,omitempty"`
+	Dependencies       []Dependency `xml:"dependencies>dependency,omitempty" json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Tags               []Tag        `xml:"tags>tag,omitempty" json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type Dependency struct {
+	Name               string `xml:"name" json:"name" yaml:"name"`
+	Expression         string `xml:"expression" json:"expression" yaml:"expression"`
+	RecoveryExpression string `xml:"recovery_expression,omitempty" json:"recovery_expression,omitempty" yaml:"recovery_expression,omitempty"`
+}
+
+type Graph struct {
+	UUID       string      `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Name       string      `xml:"name" json:"name" yaml:"name"`
+	Width      string      `xml:"width,omitempty" json:"width,omitempty" yaml:"width,omitempty"`
+	Height     string      `xml:"height,omitempty" json:"height,omitempty" yaml:"height,omitempty"`
+	Type       string      `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	GraphItems []GraphItem `xml:"graph_items>graph_item,omitempty" json:"graph_items,omitempty" yaml:"graph_items,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type GraphItem struct {
+	SortOrder string  `xml:"sortorder,omitempty" json:"sortorder,omitempty" yaml:"sortorder,omitempty"`
+	DrawType  string  `xml:"drawtype,omitempty" json:"drawtype,omitempty" yaml:"drawtype,omitempty"`
+	Color     string  `xml:"color" json:"color" yaml:"color"`
+	YAxisSide string  `xml:"yaxisside,omitempty" json:"yaxisside,omitempty" yaml:"yaxisside,omitempty"`
+	CalcFnc   string  `xml:"calc_fnc,omitempty" json:"calc_fnc,omitempty" yaml:"calc_fnc,omitempty"`
+	Type      string  `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	Item      ItemRef `xml:"item" json:"item" yaml:"item"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+// Reference to item of graph.
+type ItemRef struct {
+	Host string `xml:"host" json:"host" yaml:"host"`
+	Key  string `xml:"key" json:"key" yaml:"key"`
+}
+
+type Discovery struct {
+	UUID              string          `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Name              string          `xml:"name" json:"name" yaml:"name"`
+	Type              string          `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	Key               string          `xml:"key" json:"key" yaml:"key"`
+	Delay             string          `xml:"delay,omitempty" json:"delay,omitempty" yaml:"delay,omitempty"`
+	Status            string          `xml:"status,omitempty" json:"status,omitempty" yaml:"status,omitempty"`
+	Lifetime          string          `xml:"lifetime,omitempty" json:"lifetime,omitempty" yaml:"lifetime,omitempty"`
+	Description       string          `xml:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+	ItemPrototypes    []Item          `xml:"item_prototypes>item_prototype,omitempty" json:"item_prototypes,omitempty" yaml:"item_prototypes,omitempty"`
+	TriggerPrototypes []Trigger       `xml:"trigger_prototypes>trigger_prototype,omitempty" json:"trigger_prototypes,omitempty" yaml:"trigger_prototypes,omitempty"`
+	GraphPrototypes   []Graph         `xml:"graph_prototypes>graph_prototype,omitempty" json:"graph_prototypes,omitempty" yaml:"graph_prototypes,omitempty"`
+	HostPrototypes    []HostPrototype `xml:"host_prototypes>host_prototype,omitempty" json:"host_prototypes,omitempty" yaml:"host_prototypes,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+// Host prototype of discovery rule. Interfaces and inventory are kept in Extra fields.
+type HostPrototype struct {
+	UUID            string      `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Host            string      `xml:"host" json:"host" yaml:"host"`
+	Name            string      `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Status          string      `xml:"status,omitempty" json:"status,omitempty" yaml:"status,omitempty"`
+	GroupLinks      []GroupLink `xml:"group_links>group_link,omitempty" json:"group_links,omitempty" yaml:"group_links,omitempty"`
+	GroupPrototypes []NameRef   `xml:"group_prototypes>group_prototype,omitempty" json:"group_prototypes,omitempty" yaml:"group_prototypes,omitempty"`
+	Templates       []NameRef   `xml:"templates>template,omitempty" json:"templates,omitempty" yaml:"templates,omitempty"`
+	Macros          []Macro     `xml:"macros>macro,omitempty" json:"macros,omitempty" yaml:"macros,omitempty"`
+	Tags            []Tag       `xml:"tags>tag,omitempty" json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+// Existing host group of host prototype.
+type GroupLink struct {
+	Group NameRef `xml:"group" json:"group" yaml:"group"`
+}
+
+type Tag struct {
+	Tag   string `xml:"tag" json:"tag" yaml:"tag"`
+	Value string `xml:"value,omitempty" json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+type Macro struct {
+	Macro       string `xml:"macro" json:"macro" yaml:"macro"`
+	Value       string `xml:"value,omitempty" json:"value,omitempty" yaml:"value,omitempty"`
+	Description string `xml:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type ValueMap struct {
+	UUID     string    `xml:"uuid,omitempty" json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	Name     string    `xml:"name" json:"name" yaml:"name"`
+	Mappings []Mapping `xml:"mappings>mapping,omitempty" json:"mappings,omitempty" yaml:"mappings,omitempty"`
+
+	Extra         Fields   `xml:"-" json:"-" yaml:",inline"`
+	ExtraElements Elements `xml:",any" json:"-" yaml:"-"`
+}
+
+type Mapping struct {
+	Type     string `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	Value    string `xml:"value" json:"value" yaml:"value"`
+	NewValue string `xml:"newvalue" json:"newvalue" yaml:"newvalue"`
+}
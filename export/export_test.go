@@ -0,0 +1,365 @@
+package export_test
+
+import (
+	. "."
+	"strings"
+	"testing"
+
+	"github.com/AlekSi/zabbix"
+)
+
+const xmlExport = `<?xml version="1.0" encoding="UTF-8"?>
+<zabbix_export>
+    <version>3.0</version>
+    <date>2016-03-01T10:00:00Z</date>
+    <groups>
+        <group>
+            <name>Templates</name>
+        </group>
+    </groups>
+    <templates>
+        <template>
+            <template>Template App Web</template>
+            <name>Template App Web</name>
+            <description/>
+            <groups>
+                <group>
+                    <name>Templates</name>
+                </group>
+            </groups>
+            <applications>
+                <application>
+                    <name>Web</name>
+                </application>
+                <application>
+                    <name>CPU</name>
+                </application>
+            </applications>
+            <items>
+                <item>
+                    <name>Web status</name>
+                    <type>0</type>
+                    <key>web.status</key>
+                    <delay>60</delay>
+                    <history>7</history>
+                    <trends>365</trends>
+                    <status>0</status>
+                    <value_type>3</value_type>
+                    <description/>
+                    <applications>
+                        <application>
+                            <name>Web</name>
+                        </application>
+                    </applications>
+                    <valuemap>
+                        <name>Service state</name>
+                    </valuemap>
+                </item>
+                <item>
+                    <name>CPU load</name>
+                    <type>0</type>
+                    <key>system.cpu.load</key>
+                    <delay>60</delay>
+                    <history>7</history>
+                    <trends>365</trends>
+                    <status>0</status>
+                    <value_type>0</value_type>
+                    <description/>
+                    <applications>
+                        <application>
+                            <name>CPU</name>
+                        </application>
+                    </applications>
+                    <valuemap/>
+                </item>
+            </items>
+            <discovery_rules/>
+            <macros/>
+        </template>
+    </templates>
+    <triggers>
+        <trigger>
+            <expression>{Template App Web:web.status.last()}=0</expression>
+            <name>Web is down</name>
+            <priority>4</priority>
+            <description/>
+        </trigger>
+    </triggers>
+    <graphs>
+        <graph>
+            <name>Load</name>
+            <width>900</width>
+            <height>200</height>
+            <graph_items>
+                <graph_item>
+                    <sortorder>1</sortorder>
+                    <color>00AA00</color>
+                    <item>
+                        <host>Template App Web</host>
+                        <key>web.status</key>
+                    </item>
+                </graph_item>
+                <graph_item>
+                    <sortorder>0</sortorder>
+                    <color>1A7C11</color>
+                    <item>
+                        <host>Template App Web</host>
+                        <key>system.cpu.load</key>
+                    </item>
+                </graph_item>
+            </graph_items>
+        </graph>
+    </graphs>
+    <value_maps>
+        <value_map>
+            <name>Service state</name>
+            <mappings>
+                <mapping>
+                    <value>0</value>
+                    <newvalue>Down</newvalue>
+                </mapping>
+                <mapping>
+                    <value>1</value>
+                    <newvalue>Up</newvalue>
+                </mapping>
+            </mappings>
+        </value_map>
+    </value_maps>
+</zabbix_export>
+`
+
+const canonicalYAML = `zabbix_export:
+  version: "3.0"
+  groups:
+    - name: Templates
+  templates:
+    - template: Template App Web
+      name: Template App Web
+      groups:
+        - name: Templates
+      applications:
+        - name: CPU
+        - name: Web
+      items:
+        - name: CPU load
+          type: "0"
+          key: system.cpu.load
+          delay: "60"
+          history: "7"
+          trends: "365"
+          status: "0"
+          value_type: "0"
+          applications:
+            - name: CPU
+        - name: Web status
+          type: "0"
+          key: web.status
+          delay: "60"
+          history: "7"
+          trends: "365"
+          status: "0"
+          value_type: "3"
+          applications:
+            - name: Web
+          valuemap:
+            name: Service state
+  triggers:
+    - expression: '{Template App Web:web.status.last()}=0'
+      name: Web is down
+      priority: "4"
+  graphs:
+    - name: Load
+      width: "900"
+      height: "200"
+      graph_items:
+        - sortorder: "0"
+          color: 1A7C11
+          item:
+            host: Template App Web
+            key: system.cpu.load
+        - sortorder: "1"
+          color: 00AA00
+          item:
+            host: Template App Web
+            key: web.status
+  value_maps:
+    - name: Service state
+      mappings:
+        - value: "0"
+          newvalue: Down
+        - value: "1"
+          newvalue: Up
+`
+
+func TestCanonical(t *testing.T) {
+	e, err := Parse([]byte(xmlExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Version != "3.0" || len(e.Templates) != 1 || len(e.Templates[0].Items) != 2 || e.Templates[0].Items[1].ValueMap != nil {
+		t.Fatalf("Unexpected export %#v", e)
+	}
+
+	b, err := e.Canonical(zabbix.ExportYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != canonicalYAML {
+		t.Fatalf("Bad canonical form:\n%s", b)
+	}
+
+	for _, format := range []zabbix.ExportFormat{zabbix.ExportXML, zabbix.ExportJSON, zabbix.ExportYAML} {
+		b, err = e.Marshal(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if DetectFormat(b) != format {
+			t.Errorf("Format of %s is detected as %s", format, DetectFormat(b))
+		}
+		e2, err := Parse(b)
+		if err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+		b, _ = e2.Canonical(zabbix.ExportYAML)
+		if string(b) != canonicalYAML {
+			t.Errorf("%s round trip changed export:\n%s", format, b)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	e, err := Parse([]byte(`{"zabbix_export": {"version": "4.0", "date": "2019-01-01T00:00:00Z",
+		"templates": [{"template": "T", "items": [{"name": "Ping", "key": "agent.ping", "valuemap": []}]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Templates[0].Items[0].Key != "agent.ping" || e.Templates[0].Items[0].ValueMap != nil {
+		t.Errorf("Unexpected export %#v", e)
+	}
+
+	e.Triggers = []Trigger{{Name: "Ping & status", Expression: "last(/T/agent.ping)<>1"}}
+	b, err := e.Marshal(zabbix.ExportJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"expression": "last(/T/agent.ping)<>1"`) || !strings.Contains(string(b), `"Ping & status"`) {
+		t.Errorf("Expression is escaped:\n%s", b)
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	e, err := Parse([]byte(`zabbix_export:
+  version: '5.4'
+  templates:
+    - uuid: 7df96b18c230490a9a0a9e2307226338
+      template: T
+      items:
+        - uuid: 1e7d2d3e5c6c4f7e8e0b4c6a0f7a1b2c
+          name: Ping
+          key: agent.ping
+          history: 7d
+          triggers:
+            - expression: 'nodata(/T/agent.ping,5m)=1'
+              name: Agent is unavailable
+              priority: AVERAGE
+      valuemaps:
+        - name: Ping
+          mappings:
+            - value: '1'
+              newvalue: Up
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := e.Templates[0]
+	if tmpl.Items[0].History != "7d" || tmpl.Items[0].Triggers[0].Priority != "AVERAGE" || tmpl.ValueMaps[0].Mappings[0].NewValue != "Up" {
+		t.Errorf("Unexpected export %#v", e)
+	}
+}
+
+const extraYAML = `zabbix_export:
+  version: "5.4"
+  templates:
+    - template: T
+      items:
+        - name: Requests
+          type: DEPENDENT
+          key: web.requests
+          preprocessing:
+            - type: JSONPATH
+              parameters:
+                - $.requests
+            - type: CHANGE_PER_SECOND
+              parameters:
+                - ""
+          master_item:
+            key: web.status
+      discovery_rules:
+        - name: Sites
+          key: web.sites
+          host_prototypes:
+            - host: '{#NAME}'
+              group_links:
+                - group:
+                    name: Sites
+              templates:
+                - name: Template Site
+              interfaces:
+                - ip: 192.0.2.1
+      httptests:
+        - name: Home page
+          steps:
+            - name: Home
+              url: http://localhost/
+`
+
+func TestExtra(t *testing.T) {
+	e, err := Parse([]byte(extraYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := e.Templates[0].Items[0]
+	if len(item.Preprocessing) != 2 || item.Preprocessing[0].Parameters[0] != "$.requests" || item.Extra["master_item"] == nil {
+		t.Errorf("Unexpected item %#v", item)
+	}
+	host := e.Templates[0].DiscoveryRules[0].HostPrototypes[0]
+	if host.GroupLinks[0].Group.Name != "Sites" || host.Templates[0].Name != "Template Site" || host.Extra["interfaces"] == nil {
+		t.Errorf("Unexpected host prototype %#v", host)
+	}
+
+	for _, format := range []zabbix.ExportFormat{zabbix.ExportJSON, zabbix.ExportYAML} {
+		b, err := e.Canonical(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e2, err := Parse(b)
+		if err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+		b, _ = e2.Canonical(zabbix.ExportYAML)
+		if string(b) != extraYAML {
+			t.Errorf("%s round trip changed export:\n%s", format, b)
+		}
+	}
+
+	e, err = Parse([]byte(`<zabbix_export><version>3.0</version><templates><template><template>T</template>` +
+		`<screens><screen><name>S</name></screen></screens></template></templates><maps/></zabbix_export>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := e.Canonical(zabbix.ExportXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "<screens><screen><name>S</name></screen></screens>") || !strings.Contains(string(b), "<maps></maps>") {
+		t.Errorf("Unknown elements are lost:\n%s", b)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{`<hosts/>`, `{"hosts": []}`, `hosts: []`} {
+		if _, err := Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "zabbix_export") {
+			t.Errorf("%s: unexpected error %v", data, err)
+		}
+	}
+}
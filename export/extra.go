@@ -0,0 +1,190 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// JSON or YAML fields not described by model, like web scenarios of template.
+// They are written back to JSON and YAML, but not to XML.
+type Fields map[string]interface{}
+
+// XML element not described by model. It is written back to XML as is, but not to JSON and YAML.
+type Element struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content []byte     `xml:",innerxml"`
+}
+
+type Elements []Element
+
+// Unmarshals known fields of b to v (pointer to struct without UnmarshalJSON method)
+// and other ones to extra.
+func unmarshalJSON(b []byte, v interface{}, extra *Fields) (err error) {
+	if err = json.Unmarshal(b, v); err != nil {
+		return
+	}
+
+	var fields Fields
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&fields); err != nil {
+		return
+	}
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		delete(fields, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+	}
+	if len(fields) > 0 {
+		*extra = fields
+	}
+	return
+}
+
+// Marshals v (struct without MarshalJSON method) followed by extra fields.
+func marshalJSON(v interface{}, extra Fields) (b []byte, err error) {
+	if b, err = encodeJSON(v); err != nil || len(extra) == 0 {
+		return
+	}
+	e, err := encodeJSON(map[string]interface{}(extra))
+	if err != nil {
+		return
+	}
+	if b = b[:len(b)-1]; len(b) > 1 {
+		b = append(b, ',')
+	}
+	return append(b, e[1:]...), nil
+}
+
+func encodeJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (e *Export) UnmarshalJSON(b []byte) error {
+	type plain Export
+	return unmarshalJSON(b, (*plain)(e), &e.Extra)
+}
+
+func (e Export) MarshalJSON() ([]byte, error) {
+	type plain Export
+	return marshalJSON(plain(e), e.Extra)
+}
+
+func (g *Group) UnmarshalJSON(b []byte) error {
+	type plain Group
+	return unmarshalJSON(b, (*plain)(g), &g.Extra)
+}
+
+func (g Group) MarshalJSON() ([]byte, error) {
+	type plain Group
+	return marshalJSON(plain(g), g.Extra)
+}
+
+func (t *Template) UnmarshalJSON(b []byte) error {
+	type plain Template
+	return unmarshalJSON(b, (*plain)(t), &t.Extra)
+}
+
+func (t Template) MarshalJSON() ([]byte, error) {
+	type plain Template
+	return marshalJSON(plain(t), t.Extra)
+}
+
+func (i *Item) UnmarshalJSON(b []byte) error {
+	type plain Item
+	return unmarshalJSON(b, (*plain)(i), &i.Extra)
+}
+
+func (i Item) MarshalJSON() ([]byte, error) {
+	type plain Item
+	return marshalJSON(plain(i), i.Extra)
+}
+
+func (p *Preprocessing) UnmarshalJSON(b []byte) error {
+	type plain Preprocessing
+	return unmarshalJSON(b, (*plain)(p), &p.Extra)
+}
+
+func (p Preprocessing) MarshalJSON() ([]byte, error) {
+	type plain Preprocessing
+	return marshalJSON(plain(p), p.Extra)
+}
+
+func (t *Trigger) UnmarshalJSON(b []byte) error {
+	type plain Trigger
+	return unmarshalJSON(b, (*plain)(t), &t.Extra)
+}
+
+func (t Trigger) MarshalJSON() ([]byte, error) {
+	type plain Trigger
+	return marshalJSON(plain(t), t.Extra)
+}
+
+func (g *Graph) UnmarshalJSON(b []byte) error {
+	type plain Graph
+	return unmarshalJSON(b, (*plain)(g), &g.Extra)
+}
+
+func (g Graph) MarshalJSON() ([]byte, error) {
+	type plain Graph
+	return marshalJSON(plain(g), g.Extra)
+}
+
+func (g *GraphItem) UnmarshalJSON(b []byte) error {
+	type plain GraphItem
+	return unmarshalJSON(b, (*plain)(g), &g.Extra)
+}
+
+func (g GraphItem) MarshalJSON() ([]byte, error) {
+	type plain GraphItem
+	return marshalJSON(plain(g), g.Extra)
+}
+
+func (d *Discovery) UnmarshalJSON(b []byte) error {
+	type plain Discovery
+	return unmarshalJSON(b, (*plain)(d), &d.Extra)
+}
+
+func (d Discovery) MarshalJSON() ([]byte, error) {
+	type plain Discovery
+	return marshalJSON(plain(d), d.Extra)
+}
+
+func (h *HostPrototype) UnmarshalJSON(b []byte) error {
+	type plain HostPrototype
+	return unmarshalJSON(b, (*plain)(h), &h.Extra)
+}
+
+func (h HostPrototype) MarshalJSON() ([]byte, error) {
+	type plain HostPrototype
+	return marshalJSON(plain(h), h.Extra)
+}
+
+func (m *Macro) UnmarshalJSON(b []byte) error {
+	type plain Macro
+	return unmarshalJSON(b, (*plain)(m), &m.Extra)
+}
+
+func (m Macro) MarshalJSON() ([]byte, error) {
+	type plain Macro
+	return marshalJSON(plain(m), m.Extra)
+}
+
+func (v *ValueMap) UnmarshalJSON(b []byte) error {
+	type plain ValueMap
+	return unmarshalJSON(b, (*plain)(v), &v.Extra)
+}
+
+func (v ValueMap) MarshalJSON() ([]byte, error) {
+	type plain ValueMap
+	return marshalJSON(plain(v), v.Extra)
+}
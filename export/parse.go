@@ -0,0 +1,233 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/AlekSi/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+// Wrapper of export for JSON and YAML formats.
+type document struct {
+	Export *Export `json:"zabbix_export" yaml:"zabbix_export"`
+}
+
+// Detects format of export by first significant character.
+func DetectFormat(data []byte) zabbix.ExportFormat {
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return zabbix.ExportXML
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return zabbix.ExportJSON
+	default:
+		return zabbix.ExportYAML
+	}
+}
+
+// Reads and parses export file.
+func Load(path string) (e *Export, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	return Parse(data)
+}
+
+// Parses export in detected format.
+func Parse(data []byte) (e *Export, err error) {
+	return ParseFormat(data, DetectFormat(data))
+}
+
+// Parses export in given format.
+func ParseFormat(data []byte, format zabbix.ExportFormat) (e *Export, err error) {
+	var doc document
+	switch format {
+	case zabbix.ExportXML:
+		doc.Export = new(Export)
+		err = xml.Unmarshal(data, doc.Export)
+	case zabbix.ExportJSON:
+		err = json.Unmarshal(data, &doc)
+	case zabbix.ExportYAML:
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = fmt.Errorf("unknown export format %q", format)
+	}
+	if err == nil && doc.Export == nil {
+		err = fmt.Errorf("zabbix_export element is missing")
+	}
+	if err != nil {
+		return
+	}
+
+	e = doc.Export
+	// old exports contain empty element in place of missing value map
+	e.walkItems(func(item *Item) {
+		if item.ValueMap != nil && item.ValueMap.Name == "" {
+			item.ValueMap = nil
+		}
+	})
+	return
+}
+
+// Writes export in given format as is.
+func (e *Export) Marshal(format zabbix.ExportFormat) (b []byte, err error) {
+	switch format {
+	case zabbix.ExportXML:
+		b, err = xml.MarshalIndent(e, "", "    ")
+		if err == nil {
+			b = append([]byte(xml.Header), append(b, '\n')...)
+		}
+	case zabbix.ExportJSON:
+		// expressions like last(/host/key)<>0 are written as is, not as \u003c\u003e
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "    ")
+		err = enc.Encode(document{e})
+		b = buf.Bytes()
+	case zabbix.ExportYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err = enc.Encode(document{e}); err == nil {
+			err = enc.Close()
+		}
+		b = buf.Bytes()
+	default:
+		err = fmt.Errorf("unknown export format %q", format)
+	}
+	return
+}
+
+// Sorts objects and clears export date, so exports of the same configuration are equal
+// regardless of server object order and export time. Order of value map mappings and
+// preprocessing steps is kept, graph items are sorted by sortorder.
+func (e *Export) Canonicalize() {
+	e.Date = ""
+	sortGroups(e.Groups)
+	sortGroups(e.TemplateGroups)
+	sortTriggers(e.Triggers)
+	sortGraphs(e.Graphs)
+	sortValueMaps(e.ValueMaps)
+
+	sort.SliceStable(e.Templates, func(i, j int) bool { return e.Templates[i].Template < e.Templates[j].Template })
+	for i := range e.Templates {
+		t := &e.Templates[i]
+		sortNames(t.Templates)
+		sortNames(t.Groups)
+		sortNames(t.Applications)
+		sortItems(t.Items)
+		sortTags(t.Tags)
+		sortValueMaps(t.ValueMaps)
+		sort.SliceStable(t.Macros, func(i, j int) bool { return t.Macros[i].Macro < t.Macros[j].Macro })
+		sort.SliceStable(t.DiscoveryRules, func(i, j int) bool { return t.DiscoveryRules[i].Key < t.DiscoveryRules[j].Key })
+		for j := range t.DiscoveryRules {
+			d := &t.DiscoveryRules[j]
+			sortItems(d.ItemPrototypes)
+			sortTriggers(d.TriggerPrototypes)
+			sortGraphs(d.GraphPrototypes)
+			sortHostPrototypes(d.HostPrototypes)
+		}
+	}
+}
+
+// Canonicalizes export and writes it in given format.
+func (e *Export) Canonical(format zabbix.ExportFormat) ([]byte, error) {
+	e.Canonicalize()
+	return e.Marshal(format)
+}
+
+// Calls f for all items and item prototypes.
+func (e *Export) walkItems(f func(item *Item)) {
+	for i := range e.Templates {
+		t := &e.Templates[i]
+		for j := range t.Items {
+			f(&t.Items[j])
+		}
+		for j := range t.DiscoveryRules {
+			d := &t.DiscoveryRules[j]
+			for k := range d.ItemPrototypes {
+				f(&d.ItemPrototypes[k])
+			}
+		}
+	}
+}
+
+func sortGroups(groups []Group) {
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+}
+
+func sortNames(names []NameRef) {
+	sort.SliceStable(names, func(i, j int) bool { return names[i].Name < names[j].Name })
+}
+
+func sortHostPrototypes(hosts []HostPrototype) {
+	sort.SliceStable(hosts, func(i, j int) bool { return hosts[i].Host < hosts[j].Host })
+	for i := range hosts {
+		h := &hosts[i]
+		sort.SliceStable(h.GroupLinks, func(i, j int) bool { return h.GroupLinks[i].Group.Name < h.GroupLinks[j].Group.Name })
+		sortNames(h.GroupPrototypes)
+		sortNames(h.Templates)
+		sortTags(h.Tags)
+		sort.SliceStable(h.Macros, func(i, j int) bool { return h.Macros[i].Macro < h.Macros[j].Macro })
+	}
+}
+
+func sortTags(tags []Tag) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Tag != tags[j].Tag {
+			return tags[i].Tag < tags[j].Tag
+		}
+		return tags[i].Value < tags[j].Value
+	})
+}
+
+func sortItems(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	for i := range items {
+		sortNames(items[i].Applications)
+		sortTags(items[i].Tags)
+		sortTriggers(items[i].Triggers)
+	}
+}
+
+func sortTriggers(triggers []Trigger) {
+	sort.SliceStable(triggers, func(i, j int) bool {
+		if triggers[i].Name != triggers[j].Name {
+			return triggers[i].Name < triggers[j].Name
+		}
+		return triggers[i].Expression < triggers[j].Expression
+	})
+	for i := range triggers {
+		t := &triggers[i]
+		sortTags(t.Tags)
+		sort.SliceStable(t.Dependencies, func(i, j int) bool {
+			if t.Dependencies[i].Name != t.Dependencies[j].Name {
+				return t.Dependencies[i].Name < t.Dependencies[j].Name
+			}
+			return t.Dependencies[i].Expression < t.Dependencies[j].Expression
+		})
+	}
+}
+
+func sortGraphs(graphs []Graph) {
+	sort.SliceStable(graphs, func(i, j int) bool { return graphs[i].Name < graphs[j].Name })
+	for i := range graphs {
+		items := graphs[i].GraphItems
+		sort.SliceStable(items, func(i, j int) bool {
+			a, _ := strconv.Atoi(items[i].SortOrder)
+			b, _ := strconv.Atoi(items[j].SortOrder)
+			return a < b
+		})
+	}
+}
+
+func sortValueMaps(maps []ValueMap) {
+	sort.SliceStable(maps, func(i, j int) bool { return maps[i].Name < maps[j].Name })
+}
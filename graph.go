@@ -1,155 +1,444 @@
 package zabbix
 
-
 import (
-//       "fmt"
-        "strings"
+	"fmt"
+	"strings"
+)
+
+type (
+	GraphType    int
+	YAxisType    int
+	DrawType     int
+	CalcFunction int
+	YAxisSide    int
 )
 
+const (
+	GraphNormal   GraphType = 0
+	GraphStacked  GraphType = 1
+	GraphPie      GraphType = 2
+	GraphExploded GraphType = 3
+
+	YAxisCalculated YAxisType = 0
+	YAxisFixed      YAxisType = 1
+	YAxisItem       YAxisType = 2
+
+	DrawLine     DrawType = 0
+	DrawFilled   DrawType = 1
+	DrawBold     DrawType = 2
+	DrawDot      DrawType = 3
+	DrawDashed   DrawType = 4
+	DrawGradient DrawType = 5
+
+	CalcMin  CalcFunction = 1
+	CalcAvg  CalcFunction = 2
+	CalcMax  CalcFunction = 4
+	CalcAll  CalcFunction = 7
+	CalcLast CalcFunction = 9
 
+	YAxisLeft  YAxisSide = 0
+	YAxisRight YAxisSide = 1
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/definitions
+// Zero values of options marked with omitempty are not sent, so server defaults are used.
+// ShowLegend, ShowWorkPeriod and ShowTriggers are always sent; server default for them is 1.
 type Graph struct {
-//      Graphid    string       `json:"graphid,omitempty"`
-        Name       string       `json:"name"`
-        Gitems     GraphItems   `json:"gitems,omitempty"`
-        Height     int          `json:"height"`
-        Width      int          `json:"width"`
+	Graphid        string     `json:"graphid,omitempty"`
+	Name           string     `json:"name"`
+	Gitems         GraphItems `json:"gitems,omitempty"`
+	Height         int        `json:"height"`
+	Width          int        `json:"width"`
+	GraphType      GraphType  `json:"graphtype"`
+	ShowLegend     int        `json:"show_legend"`
+	ShowWorkPeriod int        `json:"show_work_period"`
+	ShowTriggers   int        `json:"show_triggers"`
+	Show3D         int        `json:"show_3d,omitempty"` // pie and exploded graphs only
+	YMinType       YAxisType  `json:"ymin_type"`
+	YMaxType       YAxisType  `json:"ymax_type"`
+	YAxisMin       float64    `json:"yaxismin"`              // used with YAxisFixed
+	YAxisMax       float64    `json:"yaxismax"`              // used with YAxisFixed
+	YMinItemId     string     `json:"ymin_itemid,omitempty"` // used with YAxisItem
+	YMaxItemId     string     `json:"ymax_itemid,omitempty"` // used with YAxisItem
+	PercentLeft    float64    `json:"percent_left,omitempty"`
+	PercentRight   float64    `json:"percent_right,omitempty"`
+	TemplateId     string     `json:"templateid,omitempty"`
 }
-type Graphs []Graph
 
+type Graphs []Graph
 
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphitem/definitions
 type GraphItem struct {
-//      Gitemid    string      `json:"gitemid,omitempty"`
-        Color      string      `json:"color"`
-        Itemid     string      `json:"itemid"`
+	Gitemid   string       `json:"gitemid,omitempty"`
+	Color     string       `json:"color"`
+	Itemid    string       `json:"itemid"`
+	DrawType  DrawType     `json:"drawtype"`
+	SortOrder int          `json:"sortorder"`
+	CalcFnc   CalcFunction `json:"calc_fnc,omitempty"`
+	YAxisSide YAxisSide    `json:"yaxisside"`
 }
+
 type GraphItems []GraphItem
 
+// Wrapper for graph.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/get
+// Graph items are requested too.
+func (api *API) GraphsGet(params Params) (res Graphs, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectGraphItems"]; !present {
+		params["selectGraphItems"] = "extend"
+	}
+	response, err := api.CallWithError("graph.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets graph by Id only if there is exactly 1 matching graph.
+func (api *API) GraphGetById(id string) (res *Graph, err error) {
+	graphs, err := api.GraphsGet(Params{"graphids": id})
+	if err != nil {
+		return
+	}
+
+	if len(graphs) == 1 {
+		res = &graphs[0]
+	} else {
+		e := ExpectedOneResult(len(graphs))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for graph.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/create
+// Colors of graph items are validated first.
+// Fills Graphid and Gitemid of graph items, the latter with additional graphitem.get call.
+func (api *API) GraphsCreate(graphs Graphs) (err error) {
+	if err = graphs.validateColors(); err != nil {
+		return
+	}
+
+	response, err := api.CallWithError("graph.create", graphs)
+	if err != nil {
+		return
+	}
 
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	for i, id := range graphids {
+		graphs[i].Graphid = id.(string)
+	}
+	return api.fillGraphItemIds(graphs)
+}
+
+// Wrapper for graph.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/update
+// Graph items without Gitemid are added, missing ones are removed. Fills Gitemid of added graph items.
+// Colors of graph items are validated first.
+func (api *API) GraphsUpdate(graphs Graphs) (err error) {
+	if err = graphs.validateColors(); err != nil {
+		return
+	}
+
+	update := make(Graphs, len(graphs))
+	for i, graph := range graphs {
+		graph.TemplateId = ""
+		update[i] = graph
+	}
+
+	response, err := api.CallWithError("graph.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	if len(graphs) != len(graphids) {
+		err = &ExpectedMore{len(graphs), len(graphids)}
+		return
+	}
+	return api.fillGraphItemIds(graphs)
+}
+
+func (api *API) fillGraphItemIds(graphs Graphs) (err error) {
+	ids := make([]string, len(graphs))
+	for i, graph := range graphs {
+		ids[i] = graph.Graphid
+	}
+	response, err := api.CallWithError("graphitem.get", Params{"graphids": ids, "output": "extend"})
+	if err != nil {
+		return
+	}
+
+	var gitems GraphItems
+	var gitemGraphs []struct {
+		Graphid string `json:"graphid"`
+	}
+	decodeResult(response.Result, &gitems)
+	decodeResult(response.Result, &gitemGraphs)
+
+	// graph may contain the same item several times, so used graph items are removed
+	byGraph := make(map[string]GraphItems, len(graphs))
+	for i, gitem := range gitems {
+		graphid := gitemGraphs[i].Graphid
+		byGraph[graphid] = append(byGraph[graphid], gitem)
+	}
+	for i := range graphs {
+		existing := byGraph[graphs[i].Graphid]
+		for j := range graphs[i].Gitems {
+			gitem := &graphs[i].Gitems[j]
+			for k, e := range existing {
+				if e.Itemid == gitem.Itemid && (gitem.Gitemid == "" || gitem.Gitemid == e.Gitemid) {
+					gitem.Gitemid = e.Gitemid
+					existing = append(existing[:k], existing[k+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return
+}
+
+// Copies graph to another host, mapping each graph item to the item with the same key on that host.
+// Colors, draw styles and other settings are preserved. Returns created graph
+// and keys of items which were not found on target host; their graph items are skipped,
+// and y axis limits using them fall back to calculated ones.
+func (api *API) CloneGraph(graphId, hostId string) (graph *Graph, unmatched []string, err error) {
+	source, err := api.GraphGetById(graphId)
+	if err != nil {
+		return
+	}
+
+	itemIds := make([]string, 0, len(source.Gitems)+2)
+	for _, gitem := range source.Gitems {
+		itemIds = append(itemIds, gitem.Itemid)
+	}
+	for _, id := range []string{source.YMinItemId, source.YMaxItemId} {
+		if id != "" && id != "0" {
+			itemIds = append(itemIds, id)
+		}
+	}
+	sourceItems, err := api.ItemsGet(Params{"itemids": itemIds, "output": []string{"itemid", "key_"}})
+	if err != nil {
+		return
+	}
+	keys := make(map[string]string, len(sourceItems))
+	for _, item := range sourceItems {
+		keys[item.ItemId] = item.Key
+	}
+
+	targetItems, err := api.ItemsGet(Params{"hostids": hostId, "output": []string{"itemid", "key_"}})
+	if err != nil {
+		return
+	}
+	byKey := targetItems.ByKey()
+
+	clone := *source
+	clone.Graphid, clone.TemplateId = "", ""
+	clone.Gitems = make(GraphItems, 0, len(source.Gitems))
+	for _, gitem := range source.Gitems {
+		target, found := byKey[keys[gitem.Itemid]]
+		if !found {
+			unmatched = append(unmatched, keys[gitem.Itemid])
+			continue
+		}
+		gitem.Gitemid, gitem.Itemid = "", target.ItemId
+		clone.Gitems = append(clone.Gitems, gitem)
+	}
+	if len(clone.Gitems) == 0 {
+		err = fmt.Errorf("None of %d items of graph %s found on host %s.", len(source.Gitems), graphId, hostId)
+		return
+	}
+
+	// y axis limits from items which are not found fall back to calculated ones
+	if clone.YMinType == YAxisItem {
+		clone.YMinItemId = byKey[keys[source.YMinItemId]].ItemId
+		if clone.YMinItemId == "" {
+			clone.YMinType = YAxisCalculated
+			unmatched = appendUnique(unmatched, keys[source.YMinItemId])
+		}
+	}
+	if clone.YMaxType == YAxisItem {
+		clone.YMaxItemId = byKey[keys[source.YMaxItemId]].ItemId
+		if clone.YMaxItemId == "" {
+			clone.YMaxType = YAxisCalculated
+			unmatched = appendUnique(unmatched, keys[source.YMaxItemId])
+		}
+	}
+
+	graphs := Graphs{clone}
+	err = api.GraphsCreate(graphs)
+	if err == nil {
+		graph = &graphs[0]
+	}
+	return
+}
+
+func appendUnique(keys []string, key string) []string {
+	for _, k := range keys {
+		if k == key {
+			return keys
+		}
+	}
+	return append(keys, key)
+}
+
+// Wrapper for graph.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/delete
+// Cleans Graphid in all graphs elements if call succeed.
+func (api *API) GraphsDelete(graphs Graphs) (err error) {
+	ids := make([]string, len(graphs))
+	for i, graph := range graphs {
+		ids[i] = graph.Graphid
+	}
+
+	err = api.GraphsDeleteByIds(ids)
+	if err == nil {
+		for i := range graphs {
+			graphs[i].Graphid = ""
+		}
+	}
+	return
+}
+
+// Wrapper for graph.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/delete
+func (api *API) GraphsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("graph.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	if len(ids) != len(graphids) {
+		err = &ExpectedMore{len(ids), len(graphids)}
+	}
+	return
+}
 
 func (api *API) GraphGet(intName string, params Params) (graphIds []string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graph.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _,i := range result {
-                tmp := i.(map[string]interface{})
-                graphId := tmp["graphid"].(string)
-                if strings.Contains(tmp["name"].(string), intName) {
-                            graphIds = append(graphIds, graphId)
-                }
-        }
-        return
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graph.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	for _, i := range result {
+		tmp := i.(map[string]interface{})
+		graphId := tmp["graphid"].(string)
+		if strings.Contains(tmp["name"].(string), intName) {
+			graphIds = append(graphIds, graphId)
+		}
+	}
+	return
 }
 
 func (api *API) GetGraphName(params Params) (graphName string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graph.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _,i := range result {
-                tmp := i.(map[string]interface{})
-                graphName = tmp["name"].(string)
-        }
-        return
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graph.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	for _, i := range result {
+		tmp := i.(map[string]interface{})
+		graphName = tmp["name"].(string)
+	}
+	return
 }
 
 func (api *API) GetItemKey(params Params) (itemKey string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graphitem.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _, i := range result {
-                tmp := i.(map[string]interface{})
-                if tmp["key_"].(string) != "" {
-                        itemKey = tmp["key_"].(string)
-                }
-        }
-        return
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graphitem.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	for _, i := range result {
+		tmp := i.(map[string]interface{})
+		if tmp["key_"].(string) != "" {
+			itemKey = tmp["key_"].(string)
+		}
+	}
+	return
 }
 
-
 func (api *API) GetGraphDetails(params Params) (graphDetails []interface{}, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graphitem.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        graphDetails = result
-        return
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graphitem.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	graphDetails = result
+	return
 }
 
 func (api *API) CheckHostPresence(hostId string, params Params) (res bool, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graphitem.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _,i := range result {
-                tmp := i.(map[string]interface{})
-                if tmp["hostid"].(string) == hostId {
-                        res = true
-                        break
-                } else {
-                        res = false
-                }
-        }
-        return
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graphitem.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	for _, i := range result {
+		tmp := i.(map[string]interface{})
+		if tmp["hostid"].(string) == hostId {
+			res = true
+			break
+		} else {
+			res = false
+		}
+	}
+	return
 }
 
-
 func (api *API) GetGraphItems(graphId string, params Params) (graphItems []string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graphitem.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _,i := range result {
-                tmp := i.(map[string]interface{})
-                if tmp["itemid"].(string) != "" {
-                        graphItem := tmp["itemid"].(string)
-                        graphItems = append(graphItems, graphItem)
-                }
-        }
-
-        return
-}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graphitem.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	for _, i := range result {
+		tmp := i.(map[string]interface{})
+		if tmp["itemid"].(string) != "" {
+			graphItem := tmp["itemid"].(string)
+			graphItems = append(graphItems, graphItem)
+		}
+	}
 
+	return
+}
 
 func (api *API) GetGraphItemColor(graphItemId string, params Params) (graphItemColor string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("graphitem.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _,i := range result {
-                tmp := i.(map[string]interface{})
-                if tmp["itemid"].(string) == graphItemId {
-                        graphItemColor = tmp["color"].(string)
-                }
-        }
-        return
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("graphitem.get", params)
+	if err != nil {
+		return
+	}
+	result := response.Result.([]interface{})
+	for _, i := range result {
+		tmp := i.(map[string]interface{})
+		if tmp["itemid"].(string) == graphItemId {
+			graphItemColor = tmp["color"].(string)
+		}
+	}
+	return
 }
@@ -0,0 +1,163 @@
+package zabbix
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// List of colors in Zabbix format: six hex digits without leading #.
+type Palette []string
+
+// Colors Zabbix frontend assigns to graph items by default.
+var DefaultPalette = Palette{
+	"1A7C11", "F63100", "2774A4", "A54F10", "FC6EA3", "6C59DC", "AC8C14",
+	"611F27", "F230E0", "5CCD18", "BB2A02", "5A2B57", "89ABF8", "7EC25C",
+	"274482", "2B5429", "8048B4", "FD5434", "790E1F", "87AC4D", "E89DF4",
+}
+
+var colorRegexp = regexp.MustCompile(`^[0-9A-Fa-f]{6}$`)
+
+type InvalidColor string
+
+func (e InvalidColor) Error() string {
+	return fmt.Sprintf("Invalid color %q, expected six hex digits like 00AA00.", string(e))
+}
+
+// Checks color is in format accepted by Zabbix.
+func ValidateColor(color string) error {
+	if !colorRegexp.MatchString(color) {
+		return InvalidColor(color)
+	}
+	return nil
+}
+
+// Returns first invalid color of graph items.
+func (items GraphItems) ValidateColors() error {
+	for _, item := range items {
+		if err := ValidateColor(item.Color); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (graphs Graphs) validateColors() error {
+	for _, graph := range graphs {
+		if err := graph.Gitems.ValidateColors(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns n distinct colors. Palette colors are used first,
+// then additional ones are generated with hues evenly spread by golden angle.
+// Returns nil if n is not positive.
+func (p Palette) Colors(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	colors := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+	for _, c := range p {
+		if len(colors) == n {
+			return colors
+		}
+		c = strings.ToUpper(c)
+		if !seen[c] {
+			seen[c] = true
+			colors = append(colors, c)
+		}
+	}
+
+	for i := 0; len(colors) < n; i++ {
+		hue := math.Mod(float64(i)*0.618033988749895+0.13, 1)
+		value := []float64{0.85, 0.6, 0.95}[i/7%3]
+		c := hsvToColor(hue, 0.75, value)
+		if !seen[c] {
+			seen[c] = true
+			colors = append(colors, c)
+		}
+	}
+	return colors
+}
+
+// Returns n pairs of related shades: palette color and its lighter variant.
+// Useful for in/out traffic items of the same interface. Returns nil if n is not positive.
+func (p Palette) Pairs(n int) [][2]string {
+	if n <= 0 {
+		return nil
+	}
+	pairs := make([][2]string, n)
+	for i, c := range p.Colors(n) {
+		pairs[i] = [2]string{c, Shade(c, 0.45)}
+	}
+	return pairs
+}
+
+// Sets distinct Color of every graph item.
+func (p Palette) Assign(items GraphItems) {
+	for i, c := range p.Colors(len(items)) {
+		items[i].Color = c
+	}
+}
+
+// Sets Color of graph items treating them as pairs: items 0 and 1 get related shades, then 2 and 3, and so on.
+// Last item without pair gets base color.
+func (p Palette) AssignPairs(items GraphItems) {
+	for i, pair := range p.Pairs((len(items) + 1) / 2) {
+		items[2*i].Color = pair[0]
+		if 2*i+1 < len(items) {
+			items[2*i+1].Color = pair[1]
+		}
+	}
+}
+
+// Mixes color with white (positive factor) or black (negative factor).
+// Factor is clamped to [-1, 1]; invalid color is returned unchanged.
+func Shade(color string, factor float64) string {
+	if ValidateColor(color) != nil {
+		return color
+	}
+	v, _ := strconv.ParseUint(color, 16, 32)
+	factor = math.Max(-1, math.Min(1, factor))
+
+	rgb := [3]float64{float64(v >> 16 & 0xFF), float64(v >> 8 & 0xFF), float64(v & 0xFF)}
+	for i, c := range rgb {
+		if factor > 0 {
+			rgb[i] = c + (255-c)*factor
+		} else {
+			rgb[i] = c * (1 + factor)
+		}
+	}
+	return rgbToColor(rgb[0], rgb[1], rgb[2])
+}
+
+func hsvToColor(h, s, v float64) string {
+	i := math.Floor(h * 6)
+	f := h*6 - i
+	p, q, t := v*(1-s), v*(1-f*s), v*(1-(1-f)*s)
+	var r, g, b float64
+	switch int(i) % 6 {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	case 5:
+		r, g, b = v, p, q
+	}
+	return rgbToColor(r*255, g*255, b*255)
+}
+
+func rgbToColor(r, g, b float64) string {
+	return fmt.Sprintf("%02X%02X%02X", int(math.Round(r)), int(math.Round(g)), int(math.Round(b)))
+}
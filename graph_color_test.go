@@ -0,0 +1,78 @@
+package zabbix_test
+
+import (
+	. "."
+	"testing"
+)
+
+func TestPaletteColors(t *testing.T) {
+	colors := DefaultPalette.Colors(50)
+	if len(colors) != 50 {
+		t.Fatalf("Expected 50 colors, got %d", len(colors))
+	}
+	if colors[0] != DefaultPalette[0] || colors[len(DefaultPalette)-1] != DefaultPalette[len(DefaultPalette)-1] {
+		t.Errorf("Palette colors are not used first: %v", colors)
+	}
+	seen := make(map[string]bool)
+	for _, c := range colors {
+		if err := ValidateColor(c); err != nil {
+			t.Error(err)
+		}
+		if seen[c] {
+			t.Errorf("Duplicate color %s", c)
+		}
+		seen[c] = true
+	}
+
+	colors = Palette{"ff0000", "FF0000", "00ff00"}.Colors(3)
+	if colors[0] != "FF0000" || colors[1] != "00FF00" || colors[2] == "FF0000" {
+		t.Errorf("Bad custom palette colors: %v", colors)
+	}
+
+	if colors := DefaultPalette.Colors(-1); colors != nil {
+		t.Errorf("Expected no colors, got %v", colors)
+	}
+	if pairs := DefaultPalette.Pairs(-1); pairs != nil {
+		t.Errorf("Expected no pairs, got %v", pairs)
+	}
+	if colors := DefaultPalette.Colors(0); colors != nil {
+		t.Errorf("Expected no colors, got %v", colors)
+	}
+}
+
+func TestPaletteAssignPairs(t *testing.T) {
+	items := make(GraphItems, 3)
+	Palette{"204080", "C00000"}.AssignPairs(items)
+	if items[0].Color != "204080" || items[1].Color != Shade("204080", 0.45) || items[2].Color != "C00000" {
+		t.Errorf("Bad pair colors: %#v", items)
+	}
+
+	if c := Shade("204080", 0.5); c != "90A0C0" {
+		t.Errorf("Bad lighter shade %s", c)
+	}
+	if c := Shade("204080", -0.5); c != "102040" {
+		t.Errorf("Bad darker shade %s", c)
+	}
+}
+
+func TestValidateColor(t *testing.T) {
+	for _, c := range []string{"00aa00", "FFFFFF"} {
+		if err := ValidateColor(c); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, c := range []string{"", "#00AA00", "00AA0", "GG0000"} {
+		if err := ValidateColor(c); err == nil {
+			t.Errorf("Expected error for %q", c)
+		}
+	}
+
+	items := GraphItems{{Color: "00AA00"}, {Color: "red"}}
+	if err := items.ValidateColors(); err != InvalidColor("red") {
+		t.Errorf("Unexpected error %v", err)
+	}
+	err := getAPI(t).GraphsCreate(Graphs{{Name: "bad", Gitems: items}})
+	if err != InvalidColor("red") {
+		t.Errorf("Expected InvalidColor before graph.create, got %v", err)
+	}
+}
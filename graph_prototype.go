@@ -0,0 +1,107 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphprototype/definitions
+// Graph items should reference item prototypes.
+type GraphPrototype struct {
+	GraphId    string     `json:"graphid,omitempty"`
+	Name       string     `json:"name"`
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	GraphType  GraphType  `json:"graphtype"`
+	TemplateId string     `json:"templateid,omitempty"`
+	GItems     GraphItems `json:"gitems,omitempty"`
+}
+
+type GraphPrototypes []GraphPrototype
+
+// Wrapper for graphprototype.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphprototype/get
+// Graph items are requested too.
+func (api *API) GraphPrototypesGet(params Params) (res GraphPrototypes, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectGraphItems"]; !present {
+		params["selectGraphItems"] = "extend"
+	}
+	response, err := api.CallWithError("graphprototype.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets graph prototypes by discovery rule Id.
+func (api *API) GraphPrototypesGetByRuleId(id string) (res GraphPrototypes, err error) {
+	return api.GraphPrototypesGet(Params{"discoveryids": id})
+}
+
+// Wrapper for graphprototype.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphprototype/create
+func (api *API) GraphPrototypesCreate(prototypes GraphPrototypes) (err error) {
+	response, err := api.CallWithError("graphprototype.create", prototypes)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	for i, id := range graphids {
+		prototypes[i].GraphId = id.(string)
+	}
+	return
+}
+
+// Wrapper for graphprototype.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphprototype/update
+// TemplateId is not sent as it can't be changed.
+func (api *API) GraphPrototypesUpdate(prototypes GraphPrototypes) (err error) {
+	update := make(GraphPrototypes, len(prototypes))
+	for i, prototype := range prototypes {
+		prototype.TemplateId = ""
+		update[i] = prototype
+	}
+
+	response, err := api.CallWithError("graphprototype.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	if len(prototypes) != len(graphids) {
+		err = &ExpectedMore{len(prototypes), len(graphids)}
+	}
+	return
+}
+
+// Wrapper for graphprototype.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphprototype/delete
+// Cleans GraphId in all prototypes elements if call succeed.
+func (api *API) GraphPrototypesDelete(prototypes GraphPrototypes) (err error) {
+	ids := make([]string, len(prototypes))
+	for i, prototype := range prototypes {
+		ids[i] = prototype.GraphId
+	}
+
+	err = api.GraphPrototypesDeleteByIds(ids)
+	if err == nil {
+		for i := range prototypes {
+			prototypes[i].GraphId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for graphprototype.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphprototype/delete
+func (api *API) GraphPrototypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("graphprototype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	if len(ids) != len(graphids) {
+		err = &ExpectedMore{len(ids), len(graphids)}
+	}
+	return
+}
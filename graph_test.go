@@ -0,0 +1,151 @@
+package zabbix_test
+
+import (
+	. "."
+	"testing"
+)
+
+func CreateGraph(host *Host, t *testing.T) *Graph {
+	items := Items{
+		{HostId: host.HostId, Key: "net.if.in.lala", Name: "in", Type: ZabbixTrapper, ValueType: Unsigned},
+		{HostId: host.HostId, Key: "net.if.out.lala", Name: "out", Type: ZabbixTrapper, ValueType: Unsigned},
+	}
+	err := getAPI(t).ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graphs := Graphs{{
+		Name:           "Traffic",
+		Width:          900,
+		Height:         200,
+		GraphType:      GraphStacked,
+		ShowLegend:     1,
+		ShowWorkPeriod: 1,
+		Gitems: GraphItems{
+			{Itemid: items[0].ItemId, Color: "00AA00", DrawType: DrawFilled},
+			{Itemid: items[1].ItemId, Color: "3333FF", DrawType: DrawBold, SortOrder: 1, YAxisSide: YAxisRight, CalcFnc: CalcMax},
+		},
+	}}
+	err = getAPI(t).GraphsCreate(graphs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &graphs[0]
+}
+
+func DeleteGraph(graph *Graph, t *testing.T) {
+	err := getAPI(t).GraphsDelete(Graphs{*graph})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraphs(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	graph := CreateGraph(host, t)
+	if graph.Graphid == "" || graph.Gitems[0].Gitemid == "" || graph.Gitems[1].Gitemid == "" {
+		t.Errorf("Id is empty: %#v", graph)
+	}
+
+	graph2, err := api.GraphGetById(graph.Graphid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graph2.GraphType != GraphStacked || len(graph2.Gitems) != 2 || graph2.ShowLegend != 1 || graph2.ShowTriggers != 0 {
+		t.Errorf("Bad graph: %#v", graph2)
+	}
+	for _, gitem := range graph2.Gitems {
+		if gitem.Itemid == graph.Gitems[1].Itemid && (gitem.YAxisSide != YAxisRight || gitem.CalcFnc != CalcMax || gitem.DrawType != DrawBold) {
+			t.Errorf("Bad graph item: %#v", gitem)
+		}
+	}
+
+	graph.Name = "Traffic 2"
+	graph.Gitems = graph.Gitems[:1]
+	err = api.GraphsUpdate(Graphs{*graph})
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph2, err = api.GraphGetById(graph.Graphid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graph2.Name != "Traffic 2" || len(graph2.Gitems) != 1 {
+		t.Errorf("Bad graph: %#v", graph2)
+	}
+
+	DeleteGraph(graph, t)
+}
+
+func TestCloneGraph(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	graph := CreateGraph(host, t)
+	defer DeleteGraph(graph, t)
+
+	limit := Items{{HostId: host.HostId, Key: "net.if.limit.lala", Name: "limit", Type: ZabbixTrapper, ValueType: Unsigned}}
+	err := api.ItemsCreate(limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled options differ from server defaults, so they must be copied too
+	graph.ShowLegend, graph.ShowWorkPeriod, graph.ShowTriggers = 0, 0, 0
+	graph.YMinType, graph.YMinItemId = YAxisItem, graph.Gitems[0].Itemid
+	graph.YMaxType, graph.YMaxItemId = YAxisItem, limit[0].ItemId
+	err = api.GraphsUpdate(Graphs{*graph})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host2 := CreateHost(group, t)
+	defer DeleteHost(host2, t)
+
+	items := Items{{HostId: host2.HostId, Key: "net.if.in.lala", Name: "in", Type: ZabbixTrapper, ValueType: Unsigned}}
+	err = api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone, unmatched, err := api.CloneGraph(graph.Graphid, host2.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmatched) != 2 || unmatched[0] != "net.if.out.lala" || unmatched[1] != "net.if.limit.lala" {
+		t.Errorf("Bad unmatched items: %v", unmatched)
+	}
+	if clone.Graphid == "" || clone.Graphid == graph.Graphid || len(clone.Gitems) != 1 {
+		t.Fatalf("Bad clone: %#v", clone)
+	}
+	gitem := clone.Gitems[0]
+	if gitem.Itemid != items[0].ItemId || gitem.Color != "00AA00" || gitem.DrawType != DrawFilled || gitem.Gitemid == "" {
+		t.Errorf("Bad clone item: %#v", gitem)
+	}
+
+	clone2, err := api.GraphGetById(clone.Graphid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone2.ShowLegend != 0 || clone2.ShowWorkPeriod != 0 || clone2.ShowTriggers != 0 || clone2.GraphType != GraphStacked {
+		t.Errorf("Bad clone settings: %#v", clone2)
+	}
+	if clone2.YMinType != YAxisItem || clone2.YMinItemId != items[0].ItemId || clone2.YMaxType != YAxisCalculated {
+		t.Errorf("Bad clone y axis: %#v", clone2)
+	}
+
+	DeleteGraph(clone, t)
+}
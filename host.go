@@ -1,12 +1,15 @@
 package zabbix
 
-import (
-	"github.com/AlekSi/reflector"
-)
-
 type (
-	AvailableType int
-	StatusType    int
+	AvailableType         int
+	StatusType            int
+	InventoryModeType     int
+	MaintenanceStatusType int
+	MaintenanceType       int
+	IPMIAuthType          int
+	IPMIPrivilegeType     int
+	TLSType               int
+	Select                string
 )
 
 const (
@@ -15,35 +18,141 @@ const (
 
 	Monitored   StatusType = 0
 	Unmonitored StatusType = 1
+
+	// InventoryManual is zero value and is not sent on create, use HostsUpdate with "inventory_mode" field to set it.
+	InventoryDisabled  InventoryModeType = -1
+	InventoryManual    InventoryModeType = 0
+	InventoryAutomatic InventoryModeType = 1
+
+	NoMaintenance MaintenanceStatusType = 0
+	InMaintenance MaintenanceStatusType = 1
+
+	MaintenanceWithData MaintenanceType = 0
+	MaintenanceNoData   MaintenanceType = 1
+
+	IPMIAuthDefault  IPMIAuthType = -1
+	IPMIAuthNone     IPMIAuthType = 0
+	IPMIAuthMD2      IPMIAuthType = 1
+	IPMIAuthMD5      IPMIAuthType = 2
+	IPMIAuthStraight IPMIAuthType = 4
+	IPMIAuthOEM      IPMIAuthType = 5
+	IPMIAuthRMCPPlus IPMIAuthType = 6
+
+	IPMIPrivilegeCallback IPMIPrivilegeType = 1
+	IPMIPrivilegeUser     IPMIPrivilegeType = 2
+	IPMIPrivilegeOperator IPMIPrivilegeType = 3
+	IPMIPrivilegeAdmin    IPMIPrivilegeType = 4
+	IPMIPrivilegeOEM      IPMIPrivilegeType = 5
+
+	// TLSAccept is a bitmask of these values, TLSConnect is one of them.
+	TLSNoEncryption TLSType = 1
+	TLSPSK          TLSType = 2
+	TLSCertificate  TLSType = 4
+
+	// Related objects which may be requested from HostsGet.
+	SelectInterfaces      Select = "selectInterfaces"
+	SelectGroups          Select = "selectGroups"
+	SelectParentTemplates Select = "selectParentTemplates"
+	SelectMacros          Select = "selectMacros"
+	SelectInventory       Select = "selectInventory"
+	SelectTags            Select = "selectTags"
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/definitions
+// TLS fields require Zabbix 3.0+, tags require Zabbix 4.2+.
 type Host struct {
-	HostId    string        `json:"hostid,omitempty"`
-	Host      string        `json:"host"`
-	Available AvailableType `json:"available"`
-	Error     string        `json:"error"`
-	Name      string        `json:"name"`
-	Status    StatusType    `json:"status"`
-
-	// Fields below used only when creating hosts
+	HostId            string                `json:"hostid,omitempty"`
+	Host              string                `json:"host"`
+	Available         AvailableType         `json:"available"`
+	Error             string                `json:"error"`
+	Name              string                `json:"name"`
+	Status            StatusType            `json:"status"`
+	Description       string                `json:"description,omitempty"`
+	ProxyHostId       string                `json:"proxy_hostid,omitempty"`
+	InventoryMode     InventoryModeType     `json:"inventory_mode,omitempty"`
+	IPMIAuthType      IPMIAuthType          `json:"ipmi_authtype,omitempty"`
+	IPMIPrivilege     IPMIPrivilegeType     `json:"ipmi_privilege,omitempty"`
+	IPMIUsername      string                `json:"ipmi_username,omitempty"`
+	IPMIPassword      string                `json:"ipmi_password,omitempty"`
+	TLSConnect        TLSType               `json:"tls_connect,omitempty"`
+	TLSAccept         TLSType               `json:"tls_accept,omitempty"`
+	TLSIssuer         string                `json:"tls_issuer,omitempty"`
+	TLSSubject        string                `json:"tls_subject,omitempty"`
+	TLSPSKIdentity    string                `json:"tls_psk_identity,omitempty"`
+	TLSPSK            string                `json:"tls_psk,omitempty"`
+	MaintenanceId     string                `json:"maintenanceid,omitempty"`
+	MaintenanceStatus MaintenanceStatusType `json:"maintenance_status,omitempty"`
+	MaintenanceType   MaintenanceType       `json:"maintenance_type,omitempty"`
+	MaintenanceFrom   int64                 `json:"maintenance_from,omitempty"`
+
+	// Fields below are sent when creating and updating hosts and filled by HostsGet
+	// when corresponding related objects are selected.
 	GroupIds   HostGroupIds   `json:"groups,omitempty"`
 	Interfaces HostInterfaces `json:"interfaces,omitempty"`
+	Templates  TemplateIds    `json:"templates,omitempty"`
+	Macros     UserMacros     `json:"macros,omitempty"`
+	Inventory  *HostInventory `json:"inventory,omitempty"`
+	Tags       HostTags       `json:"tags,omitempty"`
+}
+
+// https://www.zabbix.com/documentation/4.2/manual/api/reference/host/object#host_tag
+type HostTag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
 }
 
+type HostTags []HostTag
+
 type Hosts []Host
 
+type TemplateId struct {
+	TemplateId string `json:"templateid"`
+}
+
+type TemplateIds []TemplateId
+
+// Objects added by HostsMassAdd, removed by HostsMassRemove or replaced by HostsMassUpdate.
+// Nil fields are not sent.
+type HostsMass struct {
+	Groups     HostGroupIds
+	Templates  TemplateIds
+	Macros     UserMacros
+	Interfaces HostInterfaces
+}
+
+// Requests related objects with "extend" output unless params already contain select option.
+func (params Params) addSelects(selects []Select) {
+	for _, s := range selects {
+		if _, present := params[string(s)]; !present {
+			params[string(s)] = "extend"
+		}
+	}
+}
+
+// Host fields which can't be passed to host.update.
+var hostReadOnlyFields = []string{"available", "error", "maintenanceid", "maintenance_status", "maintenance_type", "maintenance_from"}
+
 // Wrapper for host.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/get
-func (api *API) HostsGet(params Params) (res Hosts, err error) {
+// Given related objects are requested with "extend" output unless params already contain select option.
+func (api *API) HostsGet(params Params, selects ...Select) (res Hosts, err error) {
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
+	params.addSelects(selects)
 	response, err := api.CallWithError("host.get", params)
 	if err != nil {
 		return
 	}
 
-	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	// parent templates are sent as "templates" on create
+	result := response.Result.([]interface{})
+	for _, r := range result {
+		m := r.(map[string]interface{})
+		if templates, present := m["parentTemplates"]; present {
+			m["templates"] = templates
+		}
+	}
+	decodeResult(result, &res)
 	return
 }
 
@@ -144,3 +253,150 @@ func (api *API) HostsDeleteByIds(ids []string) (err error) {
 	}
 	return
 }
+
+// Wrapper for host.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/update
+// Only given fields (named as in JSON, like "status" or "groups") are sent along with hostid;
+// all writable fields are sent if none given. Unknown field names are reported as error.
+func (api *API) HostsUpdate(hosts Hosts, fields ...string) (err error) {
+	update := make([]Params, len(hosts))
+	for i, host := range hosts {
+		update[i], err = updateParams(host, "hostid", hostReadOnlyFields, fields)
+		if err != nil {
+			return
+		}
+	}
+
+	response, err := api.CallWithError("host.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	if len(hosts) != len(hostids) {
+		err = &ExpectedMore{len(hosts), len(hostids)}
+	}
+	return
+}
+
+// Sets Status of hosts to Monitored with host.update.
+// Updates Status in all hosts elements if call succeed.
+func (api *API) HostsEnable(hosts Hosts) (err error) {
+	return api.hostsSetStatus(hosts, Monitored)
+}
+
+// Sets Status of hosts to Unmonitored with host.update.
+// Updates Status in all hosts elements if call succeed.
+func (api *API) HostsDisable(hosts Hosts) (err error) {
+	return api.hostsSetStatus(hosts, Unmonitored)
+}
+
+func (api *API) hostsSetStatus(hosts Hosts, status StatusType) (err error) {
+	update := make(Hosts, len(hosts))
+	for i, host := range hosts {
+		update[i] = Host{HostId: host.HostId, Status: status}
+	}
+
+	err = api.HostsUpdate(update, "status")
+	if err == nil {
+		for i := range hosts {
+			hosts[i].Status = status
+		}
+	}
+	return
+}
+
+// Wrapper for host.massadd: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/massadd
+func (api *API) HostsMassAdd(hosts Hosts, add *HostsMass) (err error) {
+	params := add.params()
+	params["hosts"] = hostIdObjects(hosts)
+	return api.hostsMass("host.massadd", len(hosts), params)
+}
+
+// Wrapper for host.massupdate: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/massupdate
+// Non-nil fields of update replace corresponding objects of hosts.
+func (api *API) HostsMassUpdate(hosts Hosts, update *HostsMass) (err error) {
+	params := update.params()
+	params["hosts"] = hostIdObjects(hosts)
+	return api.hostsMass("host.massupdate", len(hosts), params)
+}
+
+// Wrapper for host.massremove: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/massremove
+// Macros are removed by name, interfaces by IP, DNS name and port.
+func (api *API) HostsMassRemove(hosts Hosts, remove *HostsMass) (err error) {
+	ids := make([]string, len(hosts))
+	for i, host := range hosts {
+		ids[i] = host.HostId
+	}
+	params := Params{"hostids": ids}
+
+	if remove.Groups != nil {
+		groupids := make([]string, len(remove.Groups))
+		for i, group := range remove.Groups {
+			groupids[i] = group.GroupId
+		}
+		params["groupids"] = groupids
+	}
+	if remove.Templates != nil {
+		templateids := make([]string, len(remove.Templates))
+		for i, template := range remove.Templates {
+			templateids[i] = template.TemplateId
+		}
+		params["templateids"] = templateids
+	}
+	if remove.Macros != nil {
+		macros := make([]string, len(remove.Macros))
+		for i, macro := range remove.Macros {
+			macros[i] = macro.Macro
+		}
+		params["macros"] = macros
+	}
+	if remove.Interfaces != nil {
+		params["interfaces"] = remove.Interfaces
+	}
+
+	return api.hostsMass("host.massremove", len(hosts), params)
+}
+
+func (api *API) hostsMass(method string, expected int, params Params) (err error) {
+	response, err := api.CallWithError(method, params)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	if expected != len(hostids) {
+		err = &ExpectedMore{expected, len(hostids)}
+	}
+	return
+}
+
+func (m *HostsMass) params() Params {
+	params := Params{}
+	if m.Groups != nil {
+		params["groups"] = m.Groups
+	}
+	if m.Templates != nil {
+		params["templates"] = m.Templates
+	}
+	if m.Macros != nil {
+		macros := make(UserMacros, len(m.Macros))
+		for i, macro := range m.Macros {
+			macros[i] = UserMacro{Macro: macro.Macro, Value: macro.Value, Type: macro.Type, Description: macro.Description}
+		}
+		params["macros"] = macros
+	}
+	if m.Interfaces != nil {
+		params["interfaces"] = m.Interfaces
+	}
+	return params
+}
+
+func hostIdObjects(hosts Hosts) []map[string]string {
+	hostIds := make([]map[string]string, len(hosts))
+	for i, host := range hosts {
+		hostIds[i] = map[string]string{"hostid": host.HostId}
+	}
+	return hostIds
+}
@@ -1,7 +1,11 @@
 package zabbix
 
 type (
-	InterfaceType int
+	InterfaceType     int
+	SNMPVersion       int
+	SNMPSecurityLevel int
+	SNMPAuthProtocol  int
+	SNMPPrivProtocol  int
 )
 
 const (
@@ -9,16 +13,174 @@ const (
 	SNMP  InterfaceType = 2
 	IPMI  InterfaceType = 3
 	JMX   InterfaceType = 4
+
+	SNMPv1  SNMPVersion = 1
+	SNMPv2c SNMPVersion = 2
+	SNMPv3  SNMPVersion = 3
+
+	NoAuthNoPriv SNMPSecurityLevel = 0
+	AuthNoPriv   SNMPSecurityLevel = 1
+	AuthPriv     SNMPSecurityLevel = 2
+
+	AuthMD5    SNMPAuthProtocol = 0
+	AuthSHA1   SNMPAuthProtocol = 1
+	AuthSHA224 SNMPAuthProtocol = 2 // Zabbix 5.4+
+	AuthSHA256 SNMPAuthProtocol = 3 // Zabbix 5.4+
+	AuthSHA384 SNMPAuthProtocol = 4 // Zabbix 5.4+
+	AuthSHA512 SNMPAuthProtocol = 5 // Zabbix 5.4+
+
+	PrivDES     SNMPPrivProtocol = 0
+	PrivAES128  SNMPPrivProtocol = 1
+	PrivAES192  SNMPPrivProtocol = 2 // Zabbix 5.4+
+	PrivAES256  SNMPPrivProtocol = 3 // Zabbix 5.4+
+	PrivAES192C SNMPPrivProtocol = 4 // Zabbix 5.4+
+	PrivAES256C SNMPPrivProtocol = 5 // Zabbix 5.4+
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/definitions
 type HostInterface struct {
-	DNS   string        `json:"dns"`
-	IP    string        `json:"ip"`
-	Main  int           `json:"main"`
-	Port  string        `json:"port"`
-	Type  InterfaceType `json:"type"`
-	UseIP int           `json:"useip"`
+	InterfaceId string        `json:"interfaceid,omitempty"`
+	HostId      string        `json:"hostid,omitempty"`
+	DNS         string        `json:"dns"`
+	IP          string        `json:"ip"`
+	Main        int           `json:"main"`
+	Port        string        `json:"port"`
+	Type        InterfaceType `json:"type"`
+	UseIP       int           `json:"useip"`
+
+	// SNMP interfaces only, Zabbix 5.0+
+	Details *SNMPDetails `json:"details,omitempty"`
 }
 
 type HostInterfaces []HostInterface
+
+// https://www.zabbix.com/documentation/5.0/manual/api/reference/hostinterface/object#details_tag
+// Community is used by SNMPv1 and SNMPv2c, other fields by SNMPv3 only.
+// Bulk is not sent if nil, so server default 1 (use bulk requests) is used; set it to pointer to 0 to disable them.
+type SNMPDetails struct {
+	Version        SNMPVersion       `json:"version"`
+	Bulk           *int              `json:"bulk,omitempty"`
+	Community      string            `json:"community,omitempty"`
+	SecurityName   string            `json:"securityname,omitempty"`
+	SecurityLevel  SNMPSecurityLevel `json:"securitylevel,omitempty"`
+	AuthPassphrase string            `json:"authpassphrase,omitempty"`
+	PrivPassphrase string            `json:"privpassphrase,omitempty"`
+	AuthProtocol   SNMPAuthProtocol  `json:"authprotocol,omitempty"`
+	PrivProtocol   SNMPPrivProtocol  `json:"privprotocol,omitempty"`
+	ContextName    string            `json:"contextname,omitempty"`
+}
+
+// Wrapper for hostinterface.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/get
+func (api *API) HostInterfacesGet(params Params) (res HostInterfaces, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("hostinterface.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets host interfaces by host Id.
+func (api *API) HostInterfacesGetByHostId(id string) (res HostInterfaces, err error) {
+	return api.HostInterfacesGet(Params{"hostids": id})
+}
+
+// Wrapper for hostinterface.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/create
+func (api *API) HostInterfacesCreate(interfaces HostInterfaces) (err error) {
+	response, err := api.CallWithError("hostinterface.create", interfaces)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	interfaceids := result["interfaceids"].([]interface{})
+	for i, id := range interfaceids {
+		interfaces[i].InterfaceId = id.(string)
+	}
+	return
+}
+
+// Wrapper for hostinterface.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/update
+// HostId is not sent as interface can't be moved to another host.
+func (api *API) HostInterfacesUpdate(interfaces HostInterfaces) (err error) {
+	update := make(HostInterfaces, len(interfaces))
+	for i, iface := range interfaces {
+		iface.HostId = ""
+		update[i] = iface
+	}
+
+	response, err := api.CallWithError("hostinterface.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	interfaceids := result["interfaceids"].([]interface{})
+	if len(interfaces) != len(interfaceids) {
+		err = &ExpectedMore{len(interfaces), len(interfaceids)}
+	}
+	return
+}
+
+// Wrapper for hostinterface.replacehostinterfaces: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/replacehostinterfaces
+// Replaces all interfaces of host with given ones, filling InterfaceId and HostId in all interfaces elements if call succeed.
+func (api *API) HostInterfacesReplace(hostId string, interfaces HostInterfaces) (err error) {
+	replace := make(HostInterfaces, len(interfaces))
+	for i, iface := range interfaces {
+		iface.HostId = ""
+		replace[i] = iface
+	}
+
+	response, err := api.CallWithError("hostinterface.replacehostinterfaces", Params{"hostid": hostId, "interfaces": replace})
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	interfaceids := result["interfaceids"].([]interface{})
+	if len(interfaces) != len(interfaceids) {
+		err = &ExpectedMore{len(interfaces), len(interfaceids)}
+		return
+	}
+	for i, id := range interfaceids {
+		interfaces[i].InterfaceId = id.(string)
+		interfaces[i].HostId = hostId
+	}
+	return
+}
+
+// Wrapper for hostinterface.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/delete
+// Cleans InterfaceId in all interfaces elements if call succeed.
+func (api *API) HostInterfacesDelete(interfaces HostInterfaces) (err error) {
+	ids := make([]string, len(interfaces))
+	for i, iface := range interfaces {
+		ids[i] = iface.InterfaceId
+	}
+
+	err = api.HostInterfacesDeleteByIds(ids)
+	if err == nil {
+		for i := range interfaces {
+			interfaces[i].InterfaceId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for hostinterface.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/delete
+func (api *API) HostInterfacesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("hostinterface.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	interfaceids := result["interfaceids"].([]interface{})
+	if len(ids) != len(interfaceids) {
+		err = &ExpectedMore{len(ids), len(interfaceids)}
+	}
+	return
+}
@@ -0,0 +1,94 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+
+	"github.com/AlekSi/zabbix/internal/zabbixtest"
+)
+
+func TestHostInterfaces(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	ifaces, err := api.HostInterfacesGetByHostId(host.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ifaces) != 1 || ifaces[0].InterfaceId == "" || ifaces[0].HostId != host.HostId {
+		t.Fatalf("Bad interfaces: %#v", ifaces)
+	}
+
+	snmp := HostInterfaces{{HostId: host.HostId, IP: "127.0.0.1", Port: "161", Type: SNMP, UseIP: 1, Main: 1}}
+	err = api.HostInterfacesCreate(snmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snmp[0].InterfaceId == "" {
+		t.Errorf("Id is empty: %#v", snmp[0])
+	}
+
+	snmp[0].Port = "1161"
+	err = api.HostInterfacesUpdate(snmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ifaces, err = api.HostInterfacesGet(Params{"interfaceids": snmp[0].InterfaceId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ifaces) != 1 || ifaces[0].Port != "1161" {
+		t.Errorf("Bad interfaces: %#v", ifaces)
+	}
+
+	err = api.HostInterfacesDelete(snmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent := HostInterfaces{{IP: "127.0.0.1", Port: "10050", Type: Agent, UseIP: 1, Main: 1}}
+	err = api.HostInterfacesReplace(host.HostId, agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent[0].InterfaceId == "" || agent[0].HostId != host.HostId {
+		t.Errorf("Bad interface: %#v", agent[0])
+	}
+}
+
+func TestHostInterfacesSNMPBulk(t *testing.T) {
+	server := zabbixtest.Server(t, map[string]interface{}{
+		"hostinterface.get": []interface{}{
+			map[string]interface{}{"interfaceid": "1", "type": "1", "details": []interface{}{}},
+			map[string]interface{}{"interfaceid": "2", "type": "2", "details": map[string]interface{}{"version": "2", "bulk": "0", "community": "public"}},
+		},
+	})
+	defer server.Close()
+
+	ifaces, err := NewAPI(server.URL).HostInterfacesGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ifaces) != 2 || ifaces[0].Details != nil {
+		t.Fatalf("Bad interfaces: %#v", ifaces)
+	}
+	details := ifaces[1].Details
+	if details == nil || details.Version != SNMPv2c || details.Bulk == nil || *details.Bulk != 0 || details.Community != "public" {
+		t.Errorf("Bad details: %#v", details)
+	}
+
+	b, err := json.Marshal(SNMPDetails{Version: SNMPv2c, Community: "public"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"version":2,"community":"public"}`; string(b) != expected {
+		t.Errorf("Expected %s, got %s", expected, b)
+	}
+}
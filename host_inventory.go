@@ -0,0 +1,76 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/definitions#host_inventory
+// Used with InventoryManual mode; fields are filled by items in InventoryAutomatic mode.
+type HostInventory struct {
+	Alias            string `json:"alias,omitempty"`
+	AssetTag         string `json:"asset_tag,omitempty"`
+	Chassis          string `json:"chassis,omitempty"`
+	Contact          string `json:"contact,omitempty"`
+	ContractNumber   string `json:"contract_number,omitempty"`
+	DateHWDecomm     string `json:"date_hw_decomm,omitempty"`
+	DateHWExpiry     string `json:"date_hw_expiry,omitempty"`
+	DateHWInstall    string `json:"date_hw_install,omitempty"`
+	DateHWPurchase   string `json:"date_hw_purchase,omitempty"`
+	DeploymentStatus string `json:"deployment_status,omitempty"`
+	Hardware         string `json:"hardware,omitempty"`
+	HardwareFull     string `json:"hardware_full,omitempty"`
+	HostNetmask      string `json:"host_netmask,omitempty"`
+	HostNetworks     string `json:"host_networks,omitempty"`
+	HostRouter       string `json:"host_router,omitempty"`
+	HWArch           string `json:"hw_arch,omitempty"`
+	InstallerName    string `json:"installer_name,omitempty"`
+	Location         string `json:"location,omitempty"`
+	LocationLat      string `json:"location_lat,omitempty"`
+	LocationLon      string `json:"location_lon,omitempty"`
+	MACAddressA      string `json:"macaddress_a,omitempty"`
+	MACAddressB      string `json:"macaddress_b,omitempty"`
+	Model            string `json:"model,omitempty"`
+	Name             string `json:"name,omitempty"`
+	Notes            string `json:"notes,omitempty"`
+	OOBIP            string `json:"oob_ip,omitempty"`
+	OOBNetmask       string `json:"oob_netmask,omitempty"`
+	OOBRouter        string `json:"oob_router,omitempty"`
+	OS               string `json:"os,omitempty"`
+	OSFull           string `json:"os_full,omitempty"`
+	OSShort          string `json:"os_short,omitempty"`
+	POC1Cell         string `json:"poc_1_cell,omitempty"`
+	POC1Email        string `json:"poc_1_email,omitempty"`
+	POC1Name         string `json:"poc_1_name,omitempty"`
+	POC1Notes        string `json:"poc_1_notes,omitempty"`
+	POC1PhoneA       string `json:"poc_1_phone_a,omitempty"`
+	POC1PhoneB       string `json:"poc_1_phone_b,omitempty"`
+	POC1Screen       string `json:"poc_1_screen,omitempty"`
+	POC2Cell         string `json:"poc_2_cell,omitempty"`
+	POC2Email        string `json:"poc_2_email,omitempty"`
+	POC2Name         string `json:"poc_2_name,omitempty"`
+	POC2Notes        string `json:"poc_2_notes,omitempty"`
+	POC2PhoneA       string `json:"poc_2_phone_a,omitempty"`
+	POC2PhoneB       string `json:"poc_2_phone_b,omitempty"`
+	POC2Screen       string `json:"poc_2_screen,omitempty"`
+	SerialNoA        string `json:"serialno_a,omitempty"`
+	SerialNoB        string `json:"serialno_b,omitempty"`
+	SiteAddressA     string `json:"site_address_a,omitempty"`
+	SiteAddressB     string `json:"site_address_b,omitempty"`
+	SiteAddressC     string `json:"site_address_c,omitempty"`
+	SiteCity         string `json:"site_city,omitempty"`
+	SiteCountry      string `json:"site_country,omitempty"`
+	SiteNotes        string `json:"site_notes,omitempty"`
+	SiteRack         string `json:"site_rack,omitempty"`
+	SiteState        string `json:"site_state,omitempty"`
+	SiteZip          string `json:"site_zip,omitempty"`
+	Software         string `json:"software,omitempty"`
+	SoftwareAppA     string `json:"software_app_a,omitempty"`
+	SoftwareAppB     string `json:"software_app_b,omitempty"`
+	SoftwareAppC     string `json:"software_app_c,omitempty"`
+	SoftwareAppD     string `json:"software_app_d,omitempty"`
+	SoftwareAppE     string `json:"software_app_e,omitempty"`
+	SoftwareFull     string `json:"software_full,omitempty"`
+	Tag              string `json:"tag,omitempty"`
+	Type             string `json:"type,omitempty"`
+	TypeFull         string `json:"type_full,omitempty"`
+	URLA             string `json:"url_a,omitempty"`
+	URLB             string `json:"url_b,omitempty"`
+	URLC             string `json:"url_c,omitempty"`
+	Vendor           string `json:"vendor,omitempty"`
+}
@@ -0,0 +1,121 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/2.2/manual/api/reference/hostprototype/object
+// Host and Name usually contain LLD macros like {#VM.NAME}.
+type HostPrototype struct {
+	HostId     string     `json:"hostid,omitempty"`
+	RuleId     string     `json:"ruleid,omitempty"`
+	Host       string     `json:"host"`
+	Name       string     `json:"name,omitempty"`
+	Status     StatusType `json:"status"`
+	TemplateId string     `json:"templateid,omitempty"`
+
+	// Fields below are sent when creating and updating host prototypes and filled by HostPrototypesGet.
+	GroupLinks      HostGroupIds        `json:"groupLinks,omitempty"`
+	GroupPrototypes HostGroupPrototypes `json:"groupPrototypes,omitempty"`
+	Templates       TemplateIds         `json:"templates,omitempty"`
+	Tags            HostTags            `json:"tags,omitempty"` // Zabbix 4.4+
+}
+
+type HostPrototypes []HostPrototype
+
+// Host group created for discovered hosts, Name usually contains LLD macros.
+type HostGroupPrototype struct {
+	Name string `json:"name"`
+}
+
+type HostGroupPrototypes []HostGroupPrototype
+
+// Wrapper for hostprototype.get: https://www.zabbix.com/documentation/2.2/manual/api/reference/hostprototype/get
+// Group links, group prototypes and templates are requested too.
+func (api *API) HostPrototypesGet(params Params) (res HostPrototypes, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	for _, s := range []string{"selectGroupLinks", "selectGroupPrototypes", "selectTemplates"} {
+		if _, present := params[s]; !present {
+			params[s] = "extend"
+		}
+	}
+	response, err := api.CallWithError("hostprototype.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets host prototypes by discovery rule Id.
+func (api *API) HostPrototypesGetByRuleId(id string) (res HostPrototypes, err error) {
+	return api.HostPrototypesGet(Params{"discoveryids": id})
+}
+
+// Wrapper for hostprototype.create: https://www.zabbix.com/documentation/2.2/manual/api/reference/hostprototype/create
+func (api *API) HostPrototypesCreate(prototypes HostPrototypes) (err error) {
+	response, err := api.CallWithError("hostprototype.create", prototypes)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	for i, id := range hostids {
+		prototypes[i].HostId = id.(string)
+	}
+	return
+}
+
+// Wrapper for hostprototype.update: https://www.zabbix.com/documentation/2.2/manual/api/reference/hostprototype/update
+// RuleId and TemplateId are not sent as they can't be changed.
+func (api *API) HostPrototypesUpdate(prototypes HostPrototypes) (err error) {
+	update := make(HostPrototypes, len(prototypes))
+	for i, prototype := range prototypes {
+		prototype.RuleId, prototype.TemplateId = "", ""
+		update[i] = prototype
+	}
+
+	response, err := api.CallWithError("hostprototype.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	if len(prototypes) != len(hostids) {
+		err = &ExpectedMore{len(prototypes), len(hostids)}
+	}
+	return
+}
+
+// Wrapper for hostprototype.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/hostprototype/delete
+// Cleans HostId in all prototypes elements if call succeed.
+func (api *API) HostPrototypesDelete(prototypes HostPrototypes) (err error) {
+	ids := make([]string, len(prototypes))
+	for i, prototype := range prototypes {
+		ids[i] = prototype.HostId
+	}
+
+	err = api.HostPrototypesDeleteByIds(ids)
+	if err == nil {
+		for i := range prototypes {
+			prototypes[i].HostId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for hostprototype.delete: https://www.zabbix.com/documentation/2.2/manual/api/reference/hostprototype/delete
+func (api *API) HostPrototypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("hostprototype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostids := result["hostids"].([]interface{})
+	if len(ids) != len(hostids) {
+		err = &ExpectedMore{len(ids), len(hostids)}
+	}
+	return
+}
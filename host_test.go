@@ -50,23 +50,30 @@ func TestHosts(t *testing.T) {
 	if host.HostId == "" || host.Host == "" {
 		t.Errorf("Something is empty: %#v", host)
 	}
-	host.GroupIds = nil
-	host.Interfaces = nil
 
+	// server fills defaults for fields not sent on create (like TLSConnect)
 	host2, err := api.HostGetByHost(host.Host)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !reflect.DeepEqual(host, host2) {
+	if host2.HostId != host.HostId || host2.Host != host.Host || host2.Name != host.Name || host2.Status != host.Status {
 		t.Errorf("Hosts are not equal:\n%#v\n%#v", host, host2)
 	}
 
-	host2, err = api.HostGetById(host.HostId)
+	host3, err := api.HostGetById(host.HostId)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !reflect.DeepEqual(host, host2) {
-		t.Errorf("Hosts are not equal:\n%#v\n%#v", host, host2)
+	if !reflect.DeepEqual(host2, host3) {
+		t.Errorf("Hosts are not equal:\n%#v\n%#v", host2, host3)
+	}
+
+	hosts, err = api.HostsGet(Params{"hostids": host.HostId}, SelectGroups, SelectInterfaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 || !reflect.DeepEqual(hosts[0].GroupIds, HostGroupIds{{group.GroupId}}) || len(hosts[0].Interfaces) != 1 {
+		t.Errorf("Bad hosts: %#v", hosts)
 	}
 
 	hosts, err = api.HostsGetByHostGroups(HostGroups{*group})
@@ -87,3 +94,68 @@ func TestHosts(t *testing.T) {
 		t.Errorf("Bad hosts: %#v", hosts)
 	}
 }
+
+func TestHostsUpdate(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	group2 := CreateHostGroup(t)
+	defer DeleteHostGroup(group2, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	err := api.HostsDisable(Hosts{*host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	host2, err := api.HostGetById(host.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host2.Status != Unmonitored {
+		t.Errorf("Host is not disabled: %#v", host2)
+	}
+
+	host2.Name = "New name for " + host.Host
+	err = api.HostsUpdate(Hosts{*host2}, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host3, err := api.HostGetById(host.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host3.Name != host2.Name || host3.Status != Unmonitored {
+		t.Errorf("Host is not updated: %#v", host3)
+	}
+	if err = api.HostsUpdate(Hosts{*host2}, "nmae"); err == nil {
+		t.Error("Unknown field is not reported")
+	}
+
+	err = api.HostsMassAdd(Hosts{*host}, &HostsMass{Groups: HostGroupIds{{group2.GroupId}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := api.HostsGetByHostGroups(HostGroups{*group2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 {
+		t.Errorf("Bad hosts: %#v", hosts)
+	}
+
+	err = api.HostsMassRemove(Hosts{*host}, &HostsMass{Groups: HostGroupIds{{group2.GroupId}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err = api.HostsGetByHostGroups(HostGroups{*group2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 0 {
+		t.Errorf("Bad hosts: %#v", hosts)
+	}
+}
@@ -0,0 +1,86 @@
+// Package apiutil provides helpers for raw Zabbix API results shared by zabbix package and its subpackages.
+package apiutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeUnits = []struct {
+	suffix   byte
+	duration time.Duration
+}{
+	{'w', 7 * 24 * time.Hour},
+	{'d', 24 * time.Hour},
+	{'h', time.Hour},
+	{'m', time.Minute},
+	{'s', time.Second},
+}
+
+// Parses Zabbix time value like 30, 1m or 90d; number without suffix is multiplied by plain.
+// For flexible intervals (like 1m;50s/1-5,09:00-18:00) only update interval is used.
+// Empty value is 0. Returns false for values which can't be parsed, like user macros.
+func ParseDuration(s string, plain time.Duration) (d time.Duration, ok bool) {
+	s = strings.TrimSpace(strings.SplitN(s, ";", 2)[0])
+	if s == "" {
+		return 0, true
+	}
+	unit := plain
+	for _, u := range timeUnits {
+		if s[len(s)-1] == u.suffix {
+			unit = u.duration
+			s = s[:len(s)-1]
+			break
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// Formats duration with the largest Zabbix time suffix which divides it.
+func FormatDuration(d time.Duration) string {
+	for _, u := range timeUnits {
+		if d%u.duration == 0 {
+			return fmt.Sprintf("%d%c", d/u.duration, u.suffix)
+		}
+	}
+	return d.String()
+}
+
+// Checks if version string like 3.0 or 5.4.2 is older than major.minor.
+func OlderThan(version string, major, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	ma, _ := strconv.Atoi(parts[0])
+	mi := 0
+	if len(parts) > 1 {
+		mi, _ = strconv.Atoi(parts[1])
+	}
+	return ma < major || (ma == major && mi < minor)
+}
+
+// Returns string or number value as string, empty string for other values.
+func Str(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// Returns objects of array value, skipping other elements; nil for values which are not arrays.
+func Objects(v interface{}) (res []map[string]interface{}) {
+	values, _ := v.([]interface{})
+	for _, v := range values {
+		if m, ok := v.(map[string]interface{}); ok {
+			res = append(res, m)
+		}
+	}
+	return
+}
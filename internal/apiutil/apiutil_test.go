@@ -0,0 +1,49 @@
+package apiutil_test
+
+import (
+	. "."
+	"testing"
+	"time"
+)
+
+func TestOlderThan(t *testing.T) {
+	for version, expected := range map[string]bool{
+		"3.0":   true,
+		"5.2.7": true,
+		"5.4":   false,
+		"5.4.2": false,
+		"6":     false,
+	} {
+		if actual := OlderThan(version, 5, 4); actual != expected {
+			t.Errorf("OlderThan(%q, 5, 4) = %v, expected %v", version, actual, expected)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	if s := Str(float64(10050)); s != "10050" {
+		t.Errorf("Unexpected %q", s)
+	}
+	objects := Objects([]interface{}{map[string]interface{}{"host": "web1"}, "lala"})
+	if len(objects) != 1 || Str(objects[0]["host"]) != "web1" || Objects(map[string]interface{}{}) != nil {
+		t.Errorf("Unexpected %#v", objects)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"":                       0,
+		"30":                     30 * time.Minute,
+		"1m":                     time.Minute,
+		"90d":                    90 * 24 * time.Hour,
+		"2w":                     14 * 24 * time.Hour,
+		"1m;50s/1-5,09:00-18:00": time.Minute,
+	} {
+		if d, ok := ParseDuration(s, time.Minute); d != expected || !ok {
+			t.Errorf("ParseDuration(%q) = %s, %v, expected %s", s, d, ok, expected)
+		}
+	}
+	if _, ok := ParseDuration("{$DELAY}", time.Second); ok {
+		t.Error("Expected macro not to be parsed")
+	}
+}
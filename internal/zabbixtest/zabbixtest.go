@@ -0,0 +1,30 @@
+// Package zabbixtest provides fake Zabbix API server for tests of subpackages.
+package zabbixtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Starts server answering API calls with results by method name.
+// Bad requests and calls of methods without result are reported as test errors.
+func Server(t testing.TB, results map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Id     int32  `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, ok := results[req.Method]
+		if !ok {
+			t.Errorf("Unexpected call %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "result": result, "id": req.Id})
+	}))
+}
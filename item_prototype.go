@@ -0,0 +1,119 @@
+package zabbix
+
+import (
+	"github.com/AlekSi/reflector"
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/itemprototype/definitions
+// Delay, History and Trends may have suffixes like "1m", "90d" since Zabbix 3.4.
+type ItemPrototype struct {
+	ItemId      string     `json:"itemid,omitempty"`
+	HostId      string     `json:"hostid,omitempty"`
+	RuleId      string     `json:"ruleid,omitempty"`
+	InterfaceId string     `json:"interfaceid,omitempty"`
+	Key         string     `json:"key_"`
+	Name        string     `json:"name"`
+	Type        ItemType   `json:"type"`
+	ValueType   ValueType  `json:"value_type"`
+	Delay       string     `json:"delay"`
+	History     string     `json:"history,omitempty"`
+	Trends      string     `json:"trends,omitempty"`
+	Units       string     `json:"units,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Status      StatusType `json:"status"`
+	SNMPOID     string     `json:"snmp_oid,omitempty"`
+	TemplateId  string     `json:"templateid,omitempty"`
+
+	// Fields below used only when creating item prototypes
+	ApplicationIds []string `json:"applications,omitempty"`
+}
+
+type ItemPrototypes []ItemPrototype
+
+// Wrapper for itemprototype.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/itemprototype/get
+func (api *API) ItemPrototypesGet(params Params) (res ItemPrototypes, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("itemprototype.get", params)
+	if err != nil {
+		return
+	}
+
+	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	return
+}
+
+// Gets item prototypes by discovery rule Id.
+func (api *API) ItemPrototypesGetByRuleId(id string) (res ItemPrototypes, err error) {
+	return api.ItemPrototypesGet(Params{"discoveryids": id})
+}
+
+// Wrapper for itemprototype.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/itemprototype/create
+func (api *API) ItemPrototypesCreate(prototypes ItemPrototypes) (err error) {
+	response, err := api.CallWithError("itemprototype.create", prototypes)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	for i, id := range itemids {
+		prototypes[i].ItemId = id.(string)
+	}
+	return
+}
+
+// Wrapper for itemprototype.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/itemprototype/update
+// HostId, RuleId and TemplateId are not sent as they can't be changed.
+func (api *API) ItemPrototypesUpdate(prototypes ItemPrototypes) (err error) {
+	update := make(ItemPrototypes, len(prototypes))
+	for i, prototype := range prototypes {
+		prototype.HostId, prototype.RuleId, prototype.TemplateId = "", "", ""
+		update[i] = prototype
+	}
+
+	response, err := api.CallWithError("itemprototype.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	itemids := result["itemids"].([]interface{})
+	if len(prototypes) != len(itemids) {
+		err = &ExpectedMore{len(prototypes), len(itemids)}
+	}
+	return
+}
+
+// Wrapper for itemprototype.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/itemprototype/delete
+// Cleans ItemId in all prototypes elements if call succeed.
+func (api *API) ItemPrototypesDelete(prototypes ItemPrototypes) (err error) {
+	ids := make([]string, len(prototypes))
+	for i, prototype := range prototypes {
+		ids[i] = prototype.ItemId
+	}
+
+	err = api.ItemPrototypesDeleteByIds(ids)
+	if err == nil {
+		for i := range prototypes {
+			prototypes[i].ItemId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for itemprototype.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/itemprototype/delete
+func (api *API) ItemPrototypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("itemprototype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	prototypeids := result["prototypeids"].([]interface{})
+	if len(ids) != len(prototypeids) {
+		err = &ExpectedMore{len(ids), len(prototypeids)}
+	}
+	return
+}
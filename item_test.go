@@ -2,7 +2,10 @@ package zabbix_test
 
 import (
 	. "."
+	"reflect"
 	"testing"
+
+	"github.com/AlekSi/zabbix/internal/zabbixtest"
 )
 
 func CreateItem(app *Application, t *testing.T) *Item {
@@ -27,6 +30,13 @@ func DeleteItem(item *Item, t *testing.T) {
 	}
 }
 
+func TestItemsUpdateUnknownField(t *testing.T) {
+	err := NewAPI("http://host/api_jsonrpc.php").ItemsUpdate(Items{{ItemId: "1", Name: "name"}}, "name", "nmae")
+	if err == nil || err.Error() != `Unknown field "nmae".` {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
 func TestItems(t *testing.T) {
 	api := getAPI(t)
 
@@ -50,3 +60,22 @@ func TestItems(t *testing.T) {
 	item := CreateItem(app, t)
 	DeleteItem(item, t)
 }
+
+func TestGetNeighborsShortValues(t *testing.T) {
+	server := zabbixtest.Server(t, map[string]interface{}{
+		"item.get": []interface{}{
+			map[string]interface{}{"key_": "alias[1]", "prevvalue": "PRDSW01"},
+			map[string]interface{}{"key_": "alias[2]", "prevvalue": "PRDSW02.example.com"},
+			map[string]interface{}{"key_": "alias_admin[3]", "prevvalue": "admin"},
+		},
+	})
+	defer server.Close()
+
+	neighbors, err := NewAPI(server.URL).GetNeighbors(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"PRDSW01", "PRDSW02.exam"}; !reflect.DeepEqual(neighbors, expected) {
+		t.Errorf("Expected %v, got %v", expected, neighbors)
+	}
+}
@@ -0,0 +1,177 @@
+// Package lint checks hosts, templates, items and triggers against configurable rules.
+// Objects are fetched from server with Fetch or taken from export files with FromExport.
+//
+// Built-in rules are returned by Config.Rules; custom rules are plain Rule values with
+// check functions for object kinds they are interested in:
+//
+//	rules := append(lint.DefaultConfig().Rules(), lint.Rule{
+//		Name:     "item-units",
+//		Severity: lint.Info,
+//		Item: func(item *lint.Item) string {
+//			if strings.HasPrefix(item.Key, "vfs.fs.size") && item.Units == "" {
+//				return "file system size without units"
+//			}
+//			return ""
+//		},
+//	})
+//	for _, v := range lint.Lint(data, rules...) {
+//		fmt.Println(v)
+//	}
+package lint
+
+import (
+	"fmt"
+	"time"
+)
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+type Kind int
+
+const (
+	KindHost Kind = iota
+	KindTemplate
+	KindItem
+	KindTrigger
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindHost:
+		return "host"
+	case KindTemplate:
+		return "template"
+	case KindItem:
+		return "item"
+	case KindTrigger:
+		return "trigger"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Host or template.
+type Host struct {
+	Id          string // empty for objects from export files
+	Host        string // technical name
+	Name        string
+	Description string
+	Template    bool
+	Groups      []string
+	Templates   []string // technical names of linked templates
+}
+
+type Item struct {
+	Id          string // empty for objects from export files
+	Host        string // technical name of host or template
+	Name        string
+	Key         string
+	Units       string
+	Description string
+	Delay       time.Duration // 0 if unknown (user macro, for example)
+	History     time.Duration // 0 if unknown
+	Trends      time.Duration // 0 if unknown
+	Prototype   bool
+}
+
+type Trigger struct {
+	Id          string // empty for objects from export files
+	Host        string // technical name of first host or template in expression
+	Name        string
+	Expression  string
+	Description string
+	Priority    string // number or name, as in source
+	Prototype   bool
+}
+
+// Objects to check.
+type Data struct {
+	Hosts    []Host
+	Items    []Item
+	Triggers []Trigger
+}
+
+// Lint rule. Check functions return description of violation or empty string;
+// nil function means rule does not apply to that kind of objects.
+type Rule struct {
+	Name     string
+	Severity Severity
+	Host     func(host *Host) string // called for hosts and templates
+	Item     func(item *Item) string
+	Trigger  func(trigger *Trigger) string
+}
+
+// Rule violation with identifiers of object.
+type Violation struct {
+	Rule     string
+	Severity Severity
+	Kind     Kind
+	Id       string // empty for objects from export files
+	Host     string
+	Object   string // key of item, name of trigger, empty for hosts and templates
+	Message  string
+}
+
+func (v Violation) String() string {
+	object := v.Host
+	if v.Object != "" {
+		object += "/" + v.Object
+	}
+	return fmt.Sprintf("%s: %s %s: %s: %s", v.Severity, v.Kind, object, v.Rule, v.Message)
+}
+
+// Checks all objects against rules. Violations are ordered by objects, then by rules.
+func Lint(data *Data, rules ...Rule) (res []Violation) {
+	for i := range data.Hosts {
+		h := &data.Hosts[i]
+		kind := KindHost
+		if h.Template {
+			kind = KindTemplate
+		}
+		for _, r := range rules {
+			if r.Host != nil {
+				if msg := r.Host(h); msg != "" {
+					res = append(res, Violation{r.Name, r.Severity, kind, h.Id, h.Host, "", msg})
+				}
+			}
+		}
+	}
+	for i := range data.Items {
+		item := &data.Items[i]
+		for _, r := range rules {
+			if r.Item != nil {
+				if msg := r.Item(item); msg != "" {
+					res = append(res, Violation{r.Name, r.Severity, KindItem, item.Id, item.Host, item.Key, msg})
+				}
+			}
+		}
+	}
+	for i := range data.Triggers {
+		t := &data.Triggers[i]
+		for _, r := range rules {
+			if r.Trigger != nil {
+				if msg := r.Trigger(t); msg != "" {
+					res = append(res, Violation{r.Name, r.Severity, KindTrigger, t.Id, t.Host, t.Name, msg})
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,152 @@
+package lint_test
+
+import (
+	. "."
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/export"
+	"github.com/AlekSi/zabbix/internal/zabbixtest"
+)
+
+const testExport = `zabbix_export:
+  version: '5.4'
+  templates:
+    - template: Template App Web
+      items:
+        - name: Web status
+          key: web.status
+          delay: 1s
+          history: 365d
+          triggers:
+            - expression: 'last(/Template App Web/web.status)=0'
+              name: Web is down
+              priority: HIGH
+        - name: Web requests
+          key: web.requests
+          delay: '{$WEB.DELAY}'
+          history: 7d
+          units: rps
+      discovery_rules:
+        - name: Sites
+          key: web.sites
+          item_prototypes:
+            - name: Site {#NAME} status
+              key: 'web.site[{#NAME}]'
+              delay: 1m;10s/1-5,09:00-18:00
+          trigger_prototypes:
+            - expression: 'last(/Template App Web/web.site[{#NAME}])=0'
+              name: Site {#NAME} is down
+              description: Site does not respond.
+`
+
+func TestLintExport(t *testing.T) {
+	e, err := export.Parse([]byte(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := FromExport(e)
+	if len(data.Hosts) != 1 || len(data.Items) != 3 || len(data.Triggers) != 2 {
+		t.Fatalf("Unexpected data %#v", data)
+	}
+	if data.Items[2].Delay != time.Minute || !data.Items[2].Prototype || data.Triggers[1].Host != "Template App Web" {
+		t.Errorf("Unexpected data %#v", data)
+	}
+
+	rules := append(DefaultConfig().Rules(), Rule{
+		Name:     "item-units",
+		Severity: Info,
+		Item: func(item *Item) string {
+			if item.Units == "" {
+				return "item has no units"
+			}
+			return ""
+		},
+	})
+	var actual []string
+	for _, v := range Lint(data, rules...) {
+		actual = append(actual, v.String())
+	}
+	expected := []string{
+		"warning: item Template App Web/web.status: item-history: history 365d is longer than 90d",
+		"warning: item Template App Web/web.status: item-delay: delay 1s is shorter than 30s",
+		"info: item Template App Web/web.status: item-units: item has no units",
+		"info: item Template App Web/web.site[{#NAME}]: item-units: item has no units",
+		"warning: trigger Template App Web/Web is down: trigger-description: trigger has no description",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+}
+
+func TestLintOldExport(t *testing.T) {
+	e := &export.Export{Version: "3.0", Templates: []export.Template{{
+		Template: "T",
+		Items:    []export.Item{{Key: "a", Delay: "60", History: "365", Trends: "365"}},
+	}}}
+	data := FromExport(e)
+	if data.Items[0].History != 365*Day || data.Items[0].Delay != time.Minute {
+		t.Errorf("Unexpected item %#v", data.Items[0])
+	}
+	v := Lint(data, DefaultConfig().Rules()...)
+	if len(v) != 1 || v[0].Rule != "item-history" || v[0].Kind != KindItem || v[0].Object != "a" {
+		t.Errorf("Unexpected violations %v", v)
+	}
+}
+
+func TestTriggerHost(t *testing.T) {
+	for expression, host := range map[string]string{
+		"last(/Template App Web/web.status)=0":                         "Template App Web",
+		"{Template App Web:web.status.last()}=0":                       "Template App Web",
+		"{$LIMIT}<{Template App Web:web.status.last()}":                "Template App Web",
+		`{$LIMIT:"web"}<{Template App Web:web.site[{#NAME}].last()}`:   "Template App Web",
+		"{TRIGGER.VALUE}=0 and {Template App Web:web.status.last()}=0": "Template App Web",
+		"{$LIMIT}>0": "",
+	} {
+		data := FromExport(&export.Export{Version: "5.0", Triggers: []export.Trigger{{Expression: expression}}})
+		if actual := data.Triggers[0].Host; actual != host {
+			t.Errorf("%s: expected host %q, got %q", expression, host, actual)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	results := map[string]interface{}{
+		"APIInfo.version": "4.0.10",
+		"host.get": []interface{}{map[string]interface{}{
+			"hostid": "10100", "host": "web1", "name": "Web 1", "description": "",
+			"groups": []interface{}{map[string]interface{}{"name": "Linux servers"}}, "parentTemplates": []interface{}{},
+		}},
+		"item.get": []interface{}{
+			map[string]interface{}{"itemid": "1", "hostid": "10100", "key_": "own", "delay": "10s", "history": "90d", "trends": "365d", "templateid": "0", "flags": "0"},
+			map[string]interface{}{"itemid": "2", "hostid": "10100", "key_": "inherited", "delay": "1s", "history": "90d", "trends": "365d", "templateid": "5", "flags": "0"},
+		},
+		"trigger.get": []interface{}{
+			map[string]interface{}{"triggerid": "3", "description": "Down", "comments": "", "expression": "{web1:own.last()}=0", "templateid": "0", "flags": "0",
+				"hosts": []interface{}{map[string]interface{}{"host": "web1"}}},
+		},
+	}
+	server := zabbixtest.Server(t, results)
+	defer server.Close()
+
+	hostParams := zabbix.Params{"hostids": "10100"}
+	data, err := Fetch(zabbix.NewAPI(server.URL), hostParams, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(hostParams, zabbix.Params{"hostids": "10100"}) {
+		t.Errorf("Params are changed: %#v", hostParams)
+	}
+	v := Lint(data, DefaultConfig().Rules()...)
+	expected := []Violation{
+		{"host-templates", Warning, KindHost, "10100", "web1", "", "host has no linked templates"},
+		{"item-delay", Warning, KindItem, "1", "web1", "own", "delay 10s is shorter than 30s"},
+		{"trigger-description", Warning, KindTrigger, "3", "web1", "Down", "trigger has no description"},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Unexpected violations %#v", v)
+	}
+}
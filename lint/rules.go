@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/zabbix/internal/apiutil"
+)
+
+const Day = 24 * time.Hour
+
+// Configuration of built-in rules. Zero limit disables rule.
+type Config struct {
+	MaxHistory time.Duration // item-history: items storing history longer
+	MaxTrends  time.Duration // item-trends: items storing trends longer
+	MinDelay   time.Duration // item-delay: items polled more often
+
+	TriggerDescription bool // trigger-description: triggers without description
+	HostTemplates      bool // host-templates: hosts without linked templates
+
+	Severity Severity // severity of built-in rules violations
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxHistory:         90 * Day,
+		MaxTrends:          5 * 365 * Day,
+		MinDelay:           30 * time.Second,
+		TriggerDescription: true,
+		HostTemplates:      true,
+		Severity:           Warning,
+	}
+}
+
+// Returns enabled built-in rules.
+func (c Config) Rules() (rules []Rule) {
+	if c.MaxHistory > 0 {
+		rules = append(rules, Rule{Name: "item-history", Severity: c.Severity, Item: func(item *Item) string {
+			if item.History > c.MaxHistory {
+				return fmt.Sprintf("history %s is longer than %s", apiutil.FormatDuration(item.History), apiutil.FormatDuration(c.MaxHistory))
+			}
+			return ""
+		}})
+	}
+	if c.MaxTrends > 0 {
+		rules = append(rules, Rule{Name: "item-trends", Severity: c.Severity, Item: func(item *Item) string {
+			if item.Trends > c.MaxTrends {
+				return fmt.Sprintf("trends %s are longer than %s", apiutil.FormatDuration(item.Trends), apiutil.FormatDuration(c.MaxTrends))
+			}
+			return ""
+		}})
+	}
+	if c.MinDelay > 0 {
+		rules = append(rules, Rule{Name: "item-delay", Severity: c.Severity, Item: func(item *Item) string {
+			if item.Delay > 0 && item.Delay < c.MinDelay {
+				return fmt.Sprintf("delay %s is shorter than %s", apiutil.FormatDuration(item.Delay), apiutil.FormatDuration(c.MinDelay))
+			}
+			return ""
+		}})
+	}
+	if c.TriggerDescription {
+		rules = append(rules, Rule{Name: "trigger-description", Severity: c.Severity, Trigger: func(t *Trigger) string {
+			if strings.TrimSpace(t.Description) == "" {
+				return "trigger has no description"
+			}
+			return ""
+		}})
+	}
+	if c.HostTemplates {
+		rules = append(rules, Rule{Name: "host-templates", Severity: c.Severity, Host: func(h *Host) string {
+			if !h.Template && len(h.Templates) == 0 {
+				return "host has no linked templates"
+			}
+			return ""
+		}})
+	}
+	return
+}
@@ -0,0 +1,247 @@
+package lint
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/export"
+	"github.com/AlekSi/zabbix/internal/apiutil"
+)
+
+// Parses Zabbix time value like 30, 1m or 90d; number without suffix is multiplied by plain.
+// Returns 0 for values which can't be parsed, like user macros.
+func parseDuration(s string, plain time.Duration) time.Duration {
+	d, _ := apiutil.ParseDuration(s, plain)
+	return d
+}
+
+// Returns technical name of first host in trigger expression in old ({host:key.func()})
+// or new (func(/host/key)) format.
+func triggerHost(expression string) string {
+	if i := strings.Index(expression, "(/"); i >= 0 {
+		rest := expression[i+2:]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			return rest[:j]
+		}
+	}
+	// skip macros like {$LIMIT}, {#NAME} and {TRIGGER.VALUE} before the first item reference
+	for rest := expression; ; {
+		i := strings.Index(rest, "{")
+		if i < 0 {
+			return ""
+		}
+		rest = rest[i+1:]
+		token := rest
+		if j := strings.Index(token, "}"); j >= 0 {
+			token = token[:j]
+		}
+		if strings.HasPrefix(token, "$") || strings.HasPrefix(token, "#") {
+			continue
+		}
+		if j := strings.Index(token, ":"); j >= 0 {
+			return token[:j]
+		}
+	}
+}
+
+// Returns templates, items, triggers and their prototypes from export.
+func FromExport(e *export.Export) *Data {
+	// before Zabbix 3.4 history and trends are stored in days
+	storage := time.Second
+	if apiutil.OlderThan(e.Version, 3, 4) {
+		storage = Day
+	}
+
+	data := new(Data)
+	addItem := func(host string, item *export.Item, prototype bool) {
+		data.Items = append(data.Items, Item{
+			Host:        host,
+			Name:        item.Name,
+			Key:         item.Key,
+			Units:       item.Units,
+			Description: item.Description,
+			Delay:       parseDuration(item.Delay, time.Second),
+			History:     parseDuration(item.History, storage),
+			Trends:      parseDuration(item.Trends, storage),
+			Prototype:   prototype,
+		})
+	}
+	addTrigger := func(host string, t *export.Trigger, prototype bool) {
+		if host == "" {
+			host = triggerHost(t.Expression)
+		}
+		data.Triggers = append(data.Triggers, Trigger{
+			Host:        host,
+			Name:        t.Name,
+			Expression:  t.Expression,
+			Description: t.Description,
+			Priority:    t.Priority,
+			Prototype:   prototype,
+		})
+	}
+
+	for i := range e.Templates {
+		t := &e.Templates[i]
+		h := Host{Host: t.Template, Name: t.Name, Description: t.Description, Template: true}
+		for _, g := range t.Groups {
+			h.Groups = append(h.Groups, g.Name)
+		}
+		for _, l := range t.Templates {
+			h.Templates = append(h.Templates, l.Name)
+		}
+		data.Hosts = append(data.Hosts, h)
+
+		for j := range t.Items {
+			addItem(t.Template, &t.Items[j], false)
+			for k := range t.Items[j].Triggers {
+				addTrigger(t.Template, &t.Items[j].Triggers[k], false)
+			}
+		}
+		for j := range t.DiscoveryRules {
+			d := &t.DiscoveryRules[j]
+			for k := range d.ItemPrototypes {
+				addItem(t.Template, &d.ItemPrototypes[k], true)
+				for l := range d.ItemPrototypes[k].Triggers {
+					addTrigger(t.Template, &d.ItemPrototypes[k].Triggers[l], true)
+				}
+			}
+			for k := range d.TriggerPrototypes {
+				addTrigger("", &d.TriggerPrototypes[k], true)
+			}
+		}
+	}
+	for i := range e.Triggers {
+		addTrigger("", &e.Triggers[i], false)
+	}
+	return data
+}
+
+// Fetches hosts matching hostParams (for host.get) and templates matching templateParams
+// (for template.get) with their own items and triggers. Nil params skip that kind of objects;
+// given params are not changed.
+// Items and triggers inherited from templates and discovered ones are skipped,
+// prototypes are not fetched.
+func Fetch(api *zabbix.API, hostParams, templateParams zabbix.Params) (data *Data, err error) {
+	version, err := api.Version()
+	if err != nil {
+		return
+	}
+	storage := time.Second
+	if apiutil.OlderThan(version, 3, 4) {
+		storage = Day
+	}
+
+	data = new(Data)
+	hostNames := make(map[string]string) // technical names by id
+	var ids []string
+	for _, kind := range []struct {
+		method, id string
+		params     zabbix.Params
+	}{
+		{"host.get", "hostid", hostParams},
+		{"template.get", "templateid", templateParams},
+	} {
+		if kind.params == nil {
+			continue
+		}
+		params := make(zabbix.Params, len(kind.params)+3)
+		for k, v := range kind.params {
+			params[k] = v
+		}
+		params["output"] = "extend"
+		params["selectGroups"] = []string{"name"}
+		params["selectParentTemplates"] = []string{"host"}
+		var response zabbix.Response
+		response, err = api.CallWithError(kind.method, params)
+		if err != nil {
+			return
+		}
+
+		for _, r := range response.Result.([]interface{}) {
+			m := r.(map[string]interface{})
+			h := Host{
+				Id:          apiutil.Str(m[kind.id]),
+				Host:        apiutil.Str(m["host"]),
+				Name:        apiutil.Str(m["name"]),
+				Description: apiutil.Str(m["description"]),
+				Template:    kind.method == "template.get",
+				Groups:      names(m["groups"], "name"),
+				Templates:   names(m["parentTemplates"], "host"),
+			}
+			hostNames[h.Id] = h.Host
+			ids = append(ids, h.Id)
+			data.Hosts = append(data.Hosts, h)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	response, err := api.CallWithError("item.get", zabbix.Params{
+		"hostids": ids,
+		"output":  []string{"itemid", "hostid", "name", "key_", "units", "description", "delay", "history", "trends", "templateid", "flags"},
+	})
+	if err != nil {
+		return
+	}
+	for _, r := range response.Result.([]interface{}) {
+		m := r.(map[string]interface{})
+		if !own(m) {
+			continue
+		}
+		data.Items = append(data.Items, Item{
+			Id:          apiutil.Str(m["itemid"]),
+			Host:        hostNames[apiutil.Str(m["hostid"])],
+			Name:        apiutil.Str(m["name"]),
+			Key:         apiutil.Str(m["key_"]),
+			Units:       apiutil.Str(m["units"]),
+			Description: apiutil.Str(m["description"]),
+			Delay:       parseDuration(apiutil.Str(m["delay"]), time.Second),
+			History:     parseDuration(apiutil.Str(m["history"]), storage),
+			Trends:      parseDuration(apiutil.Str(m["trends"]), storage),
+		})
+	}
+
+	response, err = api.CallWithError("trigger.get", zabbix.Params{
+		"hostids":          ids,
+		"output":           []string{"triggerid", "description", "expression", "comments", "priority", "templateid", "flags"},
+		"selectHosts":      []string{"host"},
+		"expandExpression": true,
+	})
+	if err != nil {
+		return
+	}
+	for _, r := range response.Result.([]interface{}) {
+		m := r.(map[string]interface{})
+		if !own(m) {
+			continue
+		}
+		t := Trigger{
+			Id:          apiutil.Str(m["triggerid"]),
+			Name:        apiutil.Str(m["description"]),
+			Expression:  apiutil.Str(m["expression"]),
+			Description: apiutil.Str(m["comments"]),
+			Priority:    apiutil.Str(m["priority"]),
+		}
+		if hosts := names(m["hosts"], "host"); len(hosts) > 0 {
+			t.Host = hosts[0]
+		}
+		data.Triggers = append(data.Triggers, t)
+	}
+	return
+}
+
+// Checks object is neither inherited from template nor discovered.
+func own(m map[string]interface{}) bool {
+	templateId := apiutil.Str(m["templateid"])
+	return (templateId == "" || templateId == "0") && apiutil.Str(m["flags"]) != "4"
+}
+
+// Returns field of each object in array.
+func names(v interface{}, field string) (res []string) {
+	for _, m := range apiutil.Objects(v) {
+		res = append(res, apiutil.Str(m[field]))
+	}
+	return
+}
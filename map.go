@@ -0,0 +1,262 @@
+package zabbix
+
+import (
+	"strings"
+)
+
+type (
+	MapElementType int
+	LinkDrawType   int
+)
+
+const (
+	MapElementHost      MapElementType = 0
+	MapElementMap       MapElementType = 1
+	MapElementTrigger   MapElementType = 2
+	MapElementHostGroup MapElementType = 3
+	MapElementImage     MapElementType = 4
+
+	LinkLine   LinkDrawType = 0
+	LinkBold   LinkDrawType = 2
+	LinkDot    LinkDrawType = 3
+	LinkDashed LinkDrawType = 4
+)
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/map/object
+// Elements and links use Zabbix 3.4+ format.
+type Map struct {
+	SysmapId     string      `json:"sysmapid,omitempty"`
+	Name         string      `json:"name"`
+	Width        int         `json:"width"`
+	Height       int         `json:"height"`
+	BackgroundId string      `json:"backgroundid,omitempty"`
+	Private      *int        `json:"private,omitempty"` // 0 - public, 1 - private; server default 1 is used if nil
+	Elements     MapElements `json:"selements,omitempty"`
+	Links        MapLinks    `json:"links,omitempty"`
+}
+
+type Maps []Map
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/map/object#map_element
+// On create SelementId may be set to any unique value to be referenced by links of the same map.
+type MapElement struct {
+	SelementId  string            `json:"selementid,omitempty"`
+	ElementType MapElementType    `json:"elementtype"`
+	Elements    MapElementObjects `json:"elements,omitempty"` // not used for images
+	IconIdOff   string            `json:"iconid_off"`
+	Label       string            `json:"label,omitempty"`
+	X           int               `json:"x"`
+	Y           int               `json:"y"`
+}
+
+type MapElements []MapElement
+
+// Object represented by map element; only field for element type is set.
+type MapElementObject struct {
+	HostId    string `json:"hostid,omitempty"`
+	SysmapId  string `json:"sysmapid,omitempty"`
+	TriggerId string `json:"triggerid,omitempty"`
+	GroupId   string `json:"groupid,omitempty"`
+}
+
+type MapElementObjects []MapElementObject
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/map/object#map_link
+// Link is drawn with Color and DrawType unless one of its triggers is in problem state.
+type MapLink struct {
+	LinkId       string          `json:"linkid,omitempty"`
+	SelementId1  string          `json:"selementid1"`
+	SelementId2  string          `json:"selementid2"`
+	Color        string          `json:"color,omitempty"`
+	DrawType     LinkDrawType    `json:"drawtype"`
+	Label        string          `json:"label,omitempty"`
+	LinkTriggers MapLinkTriggers `json:"linktriggers,omitempty"`
+}
+
+type MapLinks []MapLink
+
+// https://www.zabbix.com/documentation/4.0/manual/api/reference/map/object#map_link_trigger
+type MapLinkTrigger struct {
+	LinkTriggerId string       `json:"linktriggerid,omitempty"`
+	TriggerId     string       `json:"triggerid"`
+	Color         string       `json:"color,omitempty"`
+	DrawType      LinkDrawType `json:"drawtype"`
+}
+
+type MapLinkTriggers []MapLinkTrigger
+
+// Adds link triggers drawn with given color and style. Triggers already attached to link are skipped.
+func (link *MapLink) AddTriggers(triggerIds []string, color string, drawType LinkDrawType) {
+	attached := make(map[string]bool, len(link.LinkTriggers))
+	for _, t := range link.LinkTriggers {
+		attached[t.TriggerId] = true
+	}
+	for _, id := range triggerIds {
+		if !attached[id] {
+			attached[id] = true
+			link.LinkTriggers = append(link.LinkTriggers, MapLinkTrigger{TriggerId: id, Color: color, DrawType: drawType})
+		}
+	}
+}
+
+// Wrapper for map.get: https://www.zabbix.com/documentation/4.0/manual/api/reference/map/get
+// Elements and links are requested too.
+func (api *API) MapsGet(params Params) (res Maps, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectSelements"]; !present {
+		params["selectSelements"] = "extend"
+	}
+	if _, present := params["selectLinks"]; !present {
+		params["selectLinks"] = "extend"
+	}
+	response, err := api.CallWithError("map.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets map by Id only if there is exactly 1 matching map.
+func (api *API) MapGetById(id string) (res *Map, err error) {
+	maps, err := api.MapsGet(Params{"sysmapids": id})
+	if err != nil {
+		return
+	}
+
+	if len(maps) == 1 {
+		res = &maps[0]
+	} else {
+		e := ExpectedOneResult(len(maps))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for map.create: https://www.zabbix.com/documentation/4.0/manual/api/reference/map/create
+// Fills SysmapId of maps, but not ids of elements and links.
+func (api *API) MapsCreate(maps Maps) (err error) {
+	response, err := api.CallWithError("map.create", maps)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	sysmapids := result["sysmapids"].([]interface{})
+	for i, id := range sysmapids {
+		maps[i].SysmapId = id.(string)
+	}
+	return
+}
+
+// Wrapper for map.update: https://www.zabbix.com/documentation/4.0/manual/api/reference/map/update
+// If Elements or Links are given, they replace existing ones.
+func (api *API) MapsUpdate(maps Maps) (err error) {
+	response, err := api.CallWithError("map.update", maps)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	sysmapids := result["sysmapids"].([]interface{})
+	if len(maps) != len(sysmapids) {
+		err = &ExpectedMore{len(maps), len(sysmapids)}
+	}
+	return
+}
+
+// Wrapper for map.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/map/delete
+// Cleans SysmapId in all maps elements if call succeed.
+func (api *API) MapsDelete(maps Maps) (err error) {
+	ids := make([]string, len(maps))
+	for i, m := range maps {
+		ids[i] = m.SysmapId
+	}
+
+	err = api.MapsDeleteByIds(ids)
+	if err == nil {
+		for i := range maps {
+			maps[i].SysmapId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for map.delete: https://www.zabbix.com/documentation/4.0/manual/api/reference/map/delete
+func (api *API) MapsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("map.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	sysmapids := result["sysmapids"].([]interface{})
+	if len(ids) != len(sysmapids) {
+		err = &ExpectedMore{len(ids), len(sysmapids)}
+	}
+	return
+}
+
+// Returns ids of triggers of given host which use items with one of interfaces as key parameter,
+// like net.if.in[eth0] or ifHCInOctets["Gi0/1"] for interface eth0 or Gi0/1.
+func (api *API) InterfaceTriggerIds(hostId string, interfaces []string) (ids []string, err error) {
+	response, err := api.CallWithError("trigger.get", Params{
+		"hostids":     hostId,
+		"output":      []string{"triggerid"},
+		"selectItems": []string{"key_"},
+	})
+	if err != nil {
+		return
+	}
+
+	for _, t := range response.Result.([]interface{}) {
+		trigger := t.(map[string]interface{})
+		items, _ := trigger["items"].([]interface{})
+		for _, i := range items {
+			key, _ := i.(map[string]interface{})["key_"].(string)
+			if keyHasParam(key, interfaces) {
+				ids = append(ids, trigger["triggerid"].(string))
+				break
+			}
+		}
+	}
+	return
+}
+
+// Adds traffic triggers of interfaces on host to link, so link is drawn with given color and style
+// when one of them is in problem state. Link of topology edge represents edge.FromInterface on edge.From host
+// and edge.ToInterface on edge.To host, so it is usually called for both of them.
+func (api *API) MapLinkAddTrafficTriggers(link *MapLink, hostId string, interfaces []string, color string, drawType LinkDrawType) (err error) {
+	if err = ValidateColor(color); err != nil {
+		return
+	}
+	ids, err := api.InterfaceTriggerIds(hostId, interfaces)
+	if err != nil {
+		return
+	}
+	link.AddTriggers(ids, color, drawType)
+	return
+}
+
+// Checks if one of item key parameters is equal to one of values, ignoring quotes.
+func keyHasParam(key string, values []string) bool {
+	start, end := strings.Index(key, "["), strings.LastIndex(key, "]")
+	if start < 0 || end < start {
+		return false
+	}
+	for _, p := range strings.Split(key[start+1:end], ",") {
+		p = strings.TrimSpace(p)
+		if len(p) >= 2 && p[0] == '"' && p[len(p)-1] == '"' {
+			p = p[1 : len(p)-1]
+		}
+		for _, v := range values {
+			if p == v {
+				return true
+			}
+		}
+	}
+	return false
+}
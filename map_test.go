@@ -0,0 +1,98 @@
+package zabbix_test
+
+import (
+	. "."
+	"testing"
+)
+
+func TestMapLinkAddTriggers(t *testing.T) {
+	link := MapLink{LinkTriggers: MapLinkTriggers{{TriggerId: "1", Color: "FF0000"}}}
+	link.AddTriggers([]string{"1", "2", "2"}, "FFAA00", LinkBold)
+	if len(link.LinkTriggers) != 2 || link.LinkTriggers[0].Color != "FF0000" {
+		t.Fatalf("Bad link triggers: %#v", link.LinkTriggers)
+	}
+	if lt := link.LinkTriggers[1]; lt.TriggerId != "2" || lt.Color != "FFAA00" || lt.DrawType != LinkBold {
+		t.Errorf("Bad link trigger: %#v", lt)
+	}
+}
+
+func TestMaps(t *testing.T) {
+	requireVersion(t, 3, 4)
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	items := Items{{HostId: host.HostId, Key: "net.if.in[eth0]", Name: "in", Type: ZabbixTrapper, ValueType: Unsigned}}
+	err := api.ItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := api.CallWithError("trigger.create", []Params{{
+		"description": "eth0 is busy",
+		"expression":  "{" + host.Host + ":net.if.in[eth0].last()}>1000000",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	triggerId := response.Result.(map[string]interface{})["triggerids"].([]interface{})[0].(string)
+
+	maps := Maps{{
+		Name:   "lala map",
+		Width:  400,
+		Height: 300,
+		Elements: MapElements{
+			{SelementId: "1", ElementType: MapElementHost, Elements: MapElementObjects{{HostId: host.HostId}}, IconIdOff: "2"},
+			{SelementId: "2", ElementType: MapElementHostGroup, Elements: MapElementObjects{{GroupId: group.GroupId}}, IconIdOff: "2", X: 200},
+		},
+		Links: MapLinks{{SelementId1: "1", SelementId2: "2", Color: "00CC00"}},
+	}}
+	err = api.MapLinkAddTrafficTriggers(&maps[0].Links[0], host.HostId, []string{"eth0"}, "DD0000", LinkBold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lt := maps[0].Links[0].LinkTriggers; len(lt) != 1 || lt[0].TriggerId != triggerId {
+		t.Fatalf("Bad link triggers: %#v", lt)
+	}
+
+	err = api.MapsCreate(maps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &maps[0]
+	if m.SysmapId == "" {
+		t.Errorf("Id is empty: %#v", m)
+	}
+
+	m2, err := api.MapGetById(m.SysmapId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m2.Private == nil || *m2.Private != 1 {
+		t.Errorf("Map is not private by default: %#v", m2.Private)
+	}
+	if len(m2.Elements) != 2 || len(m2.Links) != 1 || m2.Elements[0].Elements[0].HostId == "" {
+		t.Fatalf("Bad map: %#v", m2)
+	}
+	if lt := m2.Links[0].LinkTriggers; len(lt) != 1 || lt[0].TriggerId != triggerId || lt[0].Color != "DD0000" {
+		t.Errorf("Bad link triggers: %#v", lt)
+	}
+
+	m2.Name = "lala map 2"
+	m2.Links = nil
+	err = api.MapsUpdate(Maps{*m2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = api.MapsDelete(maps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.SysmapId != "" {
+		t.Errorf("Id is not empty: %#v", m)
+	}
+}
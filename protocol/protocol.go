@@ -0,0 +1,154 @@
+// Package protocol implements framing of Zabbix network protocol ("ZBXD" header)
+// used by sender, agents, proxies and server.
+//
+// See https://www.zabbix.com/documentation/current/manual/appendix/protocols/header_datalen
+package protocol
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+)
+
+const (
+	FlagZabbix     byte = 0x01 // always set
+	FlagCompressed byte = 0x02 // data is compressed with zlib
+	FlagLarge      byte = 0x04 // lengths are 8 bytes long
+
+	// Maximum data size accepted by Read by default, as in Zabbix server.
+	MaxDataSize = 1 << 30
+)
+
+var Header = []byte("ZBXD")
+
+type InvalidHeader []byte
+
+func (e InvalidHeader) Error() string {
+	return fmt.Sprintf("Invalid header %q.", []byte(e))
+}
+
+type TooLarge int64
+
+func (e TooLarge) Error() string {
+	return fmt.Sprintf("Data size %d exceeds limit.", int64(e))
+}
+
+// Writes data with header to w, compressing it if requested.
+func Write(w io.Writer, data []byte, compress bool) (err error) {
+	flags := FlagZabbix
+	reserved := uint32(0)
+	if compress {
+		var b bytes.Buffer
+		z := zlib.NewWriter(&b)
+		if _, err = z.Write(data); err != nil {
+			return
+		}
+		if err = z.Close(); err != nil {
+			return
+		}
+		flags |= FlagCompressed
+		reserved = uint32(len(data))
+		data = b.Bytes()
+	}
+
+	packet := make([]byte, 0, len(Header)+9+len(data))
+	packet = append(packet, Header...)
+	packet = append(packet, flags)
+	packet = binary.LittleEndian.AppendUint32(packet, uint32(len(data)))
+	packet = binary.LittleEndian.AppendUint32(packet, reserved)
+	packet = append(packet, data...)
+	_, err = w.Write(packet)
+	return
+}
+
+// Reads data with header from r, decompressing it if needed.
+// Returns TooLarge error if data (compressed or not) is larger than maxSize.
+func Read(r io.Reader, maxSize int64) (data []byte, err error) {
+	header := make([]byte, len(Header)+1)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+	if !bytes.Equal(header[:len(Header)], Header) || header[len(Header)]&FlagZabbix == 0 {
+		err = InvalidHeader(header)
+		return
+	}
+	return ReadAfterHeader(r, header[len(Header)], maxSize)
+}
+
+// Reads data length and data from r after header and flags were already read.
+// Useful for peers which may also receive plain text without header.
+func ReadAfterHeader(r io.Reader, flags byte, maxSize int64) (data []byte, err error) {
+	var length, reserved int64
+	if flags&FlagLarge != 0 {
+		lengths := make([]byte, 16)
+		if _, err = io.ReadFull(r, lengths); err != nil {
+			return
+		}
+		length = int64(binary.LittleEndian.Uint64(lengths[:8]))
+		reserved = int64(binary.LittleEndian.Uint64(lengths[8:]))
+	} else {
+		lengths := make([]byte, 8)
+		if _, err = io.ReadFull(r, lengths); err != nil {
+			return
+		}
+		length = int64(binary.LittleEndian.Uint32(lengths[:4]))
+		reserved = int64(binary.LittleEndian.Uint32(lengths[4:]))
+	}
+
+	if length < 0 || length > maxSize {
+		err = TooLarge(length)
+		return
+	}
+	data = make([]byte, length)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return
+	}
+
+	if flags&FlagCompressed == 0 {
+		return
+	}
+	if reserved < 0 || reserved > maxSize {
+		err = TooLarge(reserved)
+		return
+	}
+	z, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	defer z.Close()
+	data, err = ioutil.ReadAll(io.LimitReader(z, reserved))
+	return
+}
+
+// Connects to addr, sends request marshaled to JSON and unmarshals JSON reply to response.
+// Timeout applies to the whole exchange, zero means no timeout.
+func Exchange(addr string, timeout time.Duration, compress bool, request, response interface{}) (err error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return
+	}
+	if err = Write(conn, b, compress); err != nil {
+		return
+	}
+
+	b, err = Read(conn, MaxDataSize)
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(b, response)
+}
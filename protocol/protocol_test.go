@@ -0,0 +1,46 @@
+package protocol_test
+
+import (
+	. "."
+	"bytes"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		var b bytes.Buffer
+		data := bytes.Repeat([]byte(`{"request":"sender data"}`), 100)
+		err := Write(&b, data, compress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Bytes()[4] != FlagZabbix && !compress {
+			t.Errorf("Bad flags: %x", b.Bytes()[4])
+		}
+		if compress && b.Len() >= len(data) {
+			t.Errorf("Data is not compressed: %d", b.Len())
+		}
+
+		data2, err := Read(&b, MaxDataSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, data2) {
+			t.Errorf("Data is not equal:\n%s\n%s", data, data2)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("HTTP/1.0 200 OK\r\n")), MaxDataSize)
+	if _, ok := err.(InvalidHeader); !ok {
+		t.Errorf("Expected InvalidHeader, got %v", err)
+	}
+
+	var b bytes.Buffer
+	Write(&b, []byte("0123456789"), false)
+	_, err = Read(&b, 5)
+	if _, ok := err.(TooLarge); !ok {
+		t.Errorf("Expected TooLarge, got %v", err)
+	}
+}
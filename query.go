@@ -0,0 +1,415 @@
+package zabbix
+
+// Parameters common for all get methods, filled by query builders.
+type query Params
+
+func (q query) ids(key string, ids []string) {
+	q[key] = ids
+}
+
+func (q query) filter(field string, values []string) {
+	filter, _ := q["filter"].(map[string]interface{})
+	if filter == nil {
+		filter = make(map[string]interface{})
+		q["filter"] = filter
+	}
+	if len(values) == 1 {
+		filter[field] = values[0]
+	} else {
+		filter[field] = values
+	}
+}
+
+func (q query) search(field, value string) {
+	search, _ := q["search"].(map[string]string)
+	if search == nil {
+		search = make(map[string]string)
+		q["search"] = search
+	}
+	search[field] = value
+}
+
+// Adds sort field; sortorder lists direction of each sort field.
+func (q query) sortBy(field string, desc bool) {
+	fields, _ := q["sortfield"].([]string)
+	q["sortfield"] = append(fields, field)
+	order := "ASC"
+	if desc {
+		order = "DESC"
+	}
+	orders, _ := q["sortorder"].([]string)
+	q["sortorder"] = append(orders, order)
+}
+
+// Returns copy of query, so builder may be reused without changing already returned Params.
+func (q query) params() Params {
+	params := make(Params, len(q))
+	for k, v := range q {
+		switch v := v.(type) {
+		case []string:
+			params[k] = append([]string(nil), v...)
+		case map[string]string:
+			m := make(map[string]string, len(v))
+			for f, value := range v {
+				m[f] = value
+			}
+			params[k] = m
+		case map[string]interface{}:
+			m := make(map[string]interface{}, len(v))
+			for f, value := range v {
+				if values, ok := value.([]string); ok {
+					value = append([]string(nil), values...)
+				}
+				m[f] = value
+			}
+			params[k] = m
+		default:
+			params[k] = v
+		}
+	}
+	return params
+}
+
+// Builds Params for HostsGet.
+type HostQueryBuilder struct {
+	q query
+}
+
+// Starts building Params for HostsGet.
+func HostQuery() *HostQueryBuilder {
+	return &HostQueryBuilder{query{}}
+}
+
+// Returns only hosts with given Ids.
+func (b *HostQueryBuilder) Ids(ids ...string) *HostQueryBuilder {
+	b.q.ids("hostids", ids)
+	return b
+}
+
+// Returns only hosts belonging to given host groups.
+func (b *HostQueryBuilder) InGroups(groupIds ...string) *HostQueryBuilder {
+	b.q.ids("groupids", groupIds)
+	return b
+}
+
+// Returns only hosts linked to given templates.
+func (b *HostQueryBuilder) WithTemplates(templateIds ...string) *HostQueryBuilder {
+	b.q.ids("templateids", templateIds)
+	return b
+}
+
+// Returns only hosts having given items.
+func (b *HostQueryBuilder) WithItems(itemIds ...string) *HostQueryBuilder {
+	b.q.ids("itemids", itemIds)
+	return b
+}
+
+// Returns only monitored hosts.
+func (b *HostQueryBuilder) Monitored() *HostQueryBuilder {
+	b.q["monitored_hosts"] = true
+	return b
+}
+
+// Returns only hosts where field exactly matches one of values.
+func (b *HostQueryBuilder) Filter(field string, values ...string) *HostQueryBuilder {
+	b.q.filter(field, values)
+	return b
+}
+
+// Returns only hosts where field contains value.
+func (b *HostQueryBuilder) Search(field, value string) *HostQueryBuilder {
+	b.q.search(field, value)
+	return b
+}
+
+// Limits number of returned hosts.
+func (b *HostQueryBuilder) Limit(limit int) *HostQueryBuilder {
+	b.q["limit"] = limit
+	return b
+}
+
+// Returns only given fields instead of all.
+func (b *HostQueryBuilder) Output(fields ...string) *HostQueryBuilder {
+	b.q["output"] = fields
+	return b
+}
+
+// Sorts hosts by field; may be called several times.
+func (b *HostQueryBuilder) SortBy(field string, desc bool) *HostQueryBuilder {
+	b.q.sortBy(field, desc)
+	return b
+}
+
+// Requests related objects to be filled in returned hosts.
+func (b *HostQueryBuilder) Select(selects ...Select) *HostQueryBuilder {
+	for _, s := range selects {
+		b.q[string(s)] = "extend"
+	}
+	return b
+}
+
+// Fills Interfaces of returned hosts.
+func (b *HostQueryBuilder) SelectInterfaces() *HostQueryBuilder {
+	return b.Select(SelectInterfaces)
+}
+
+// Fills GroupIds of returned hosts.
+func (b *HostQueryBuilder) SelectGroups() *HostQueryBuilder {
+	return b.Select(SelectGroups)
+}
+
+// Fills Templates of returned hosts.
+func (b *HostQueryBuilder) SelectParentTemplates() *HostQueryBuilder {
+	return b.Select(SelectParentTemplates)
+}
+
+// Fills Macros of returned hosts.
+func (b *HostQueryBuilder) SelectMacros() *HostQueryBuilder {
+	return b.Select(SelectMacros)
+}
+
+// Fills Inventory of returned hosts.
+func (b *HostQueryBuilder) SelectInventory() *HostQueryBuilder {
+	return b.Select(SelectInventory)
+}
+
+// Fills Tags of returned hosts.
+func (b *HostQueryBuilder) SelectTags() *HostQueryBuilder {
+	return b.Select(SelectTags)
+}
+
+// Returns built Params. Builder may be reused after that.
+func (b *HostQueryBuilder) Params() Params {
+	return b.q.params()
+}
+
+// Builds Params for ItemsGet.
+type ItemQueryBuilder struct {
+	q query
+}
+
+// Starts building Params for ItemsGet.
+func ItemQuery() *ItemQueryBuilder {
+	return &ItemQueryBuilder{query{}}
+}
+
+// Returns only items with given Ids.
+func (b *ItemQueryBuilder) Ids(ids ...string) *ItemQueryBuilder {
+	b.q.ids("itemids", ids)
+	return b
+}
+
+// Returns only items belonging to given hosts.
+func (b *ItemQueryBuilder) OnHosts(hostIds ...string) *ItemQueryBuilder {
+	b.q.ids("hostids", hostIds)
+	return b
+}
+
+// Returns only items belonging to hosts from given host groups.
+func (b *ItemQueryBuilder) InGroups(groupIds ...string) *ItemQueryBuilder {
+	b.q.ids("groupids", groupIds)
+	return b
+}
+
+// Returns only items belonging to given templates.
+func (b *ItemQueryBuilder) WithTemplates(templateIds ...string) *ItemQueryBuilder {
+	b.q.ids("templateids", templateIds)
+	return b
+}
+
+// Returns only items belonging to given applications.
+func (b *ItemQueryBuilder) InApplications(applicationIds ...string) *ItemQueryBuilder {
+	b.q.ids("applicationids", applicationIds)
+	return b
+}
+
+// Returns only enabled items of monitored hosts.
+func (b *ItemQueryBuilder) Monitored() *ItemQueryBuilder {
+	b.q["monitored"] = true
+	return b
+}
+
+// Returns only items where field exactly matches one of values.
+func (b *ItemQueryBuilder) Filter(field string, values ...string) *ItemQueryBuilder {
+	b.q.filter(field, values)
+	return b
+}
+
+// Returns only items where field contains value.
+func (b *ItemQueryBuilder) Search(field, value string) *ItemQueryBuilder {
+	b.q.search(field, value)
+	return b
+}
+
+// Limits number of returned items.
+func (b *ItemQueryBuilder) Limit(limit int) *ItemQueryBuilder {
+	b.q["limit"] = limit
+	return b
+}
+
+// Returns only given fields instead of all.
+func (b *ItemQueryBuilder) Output(fields ...string) *ItemQueryBuilder {
+	b.q["output"] = fields
+	return b
+}
+
+// Sorts items by field; may be called several times.
+func (b *ItemQueryBuilder) SortBy(field string, desc bool) *ItemQueryBuilder {
+	b.q.sortBy(field, desc)
+	return b
+}
+
+// Returns built Params. Builder may be reused after that.
+func (b *ItemQueryBuilder) Params() Params {
+	return b.q.params()
+}
+
+// Builds Params for ApplicationsGet.
+type ApplicationQueryBuilder struct {
+	q query
+}
+
+// Starts building Params for ApplicationsGet.
+func ApplicationQuery() *ApplicationQueryBuilder {
+	return &ApplicationQueryBuilder{query{}}
+}
+
+// Returns only applications with given Ids.
+func (b *ApplicationQueryBuilder) Ids(ids ...string) *ApplicationQueryBuilder {
+	b.q.ids("applicationids", ids)
+	return b
+}
+
+// Returns only applications belonging to given hosts.
+func (b *ApplicationQueryBuilder) OnHosts(hostIds ...string) *ApplicationQueryBuilder {
+	b.q.ids("hostids", hostIds)
+	return b
+}
+
+// Returns only applications belonging to hosts from given host groups.
+func (b *ApplicationQueryBuilder) InGroups(groupIds ...string) *ApplicationQueryBuilder {
+	b.q.ids("groupids", groupIds)
+	return b
+}
+
+// Returns only applications belonging to given templates.
+func (b *ApplicationQueryBuilder) WithTemplates(templateIds ...string) *ApplicationQueryBuilder {
+	b.q.ids("templateids", templateIds)
+	return b
+}
+
+// Returns only applications containing given items.
+func (b *ApplicationQueryBuilder) WithItems(itemIds ...string) *ApplicationQueryBuilder {
+	b.q.ids("itemids", itemIds)
+	return b
+}
+
+// Returns only applications where field exactly matches one of values.
+func (b *ApplicationQueryBuilder) Filter(field string, values ...string) *ApplicationQueryBuilder {
+	b.q.filter(field, values)
+	return b
+}
+
+// Returns only applications where field contains value.
+func (b *ApplicationQueryBuilder) Search(field, value string) *ApplicationQueryBuilder {
+	b.q.search(field, value)
+	return b
+}
+
+// Limits number of returned applications.
+func (b *ApplicationQueryBuilder) Limit(limit int) *ApplicationQueryBuilder {
+	b.q["limit"] = limit
+	return b
+}
+
+// Returns only given fields instead of all.
+func (b *ApplicationQueryBuilder) Output(fields ...string) *ApplicationQueryBuilder {
+	b.q["output"] = fields
+	return b
+}
+
+// Sorts applications by field; may be called several times.
+func (b *ApplicationQueryBuilder) SortBy(field string, desc bool) *ApplicationQueryBuilder {
+	b.q.sortBy(field, desc)
+	return b
+}
+
+// Returns built Params. Builder may be reused after that.
+func (b *ApplicationQueryBuilder) Params() Params {
+	return b.q.params()
+}
+
+// Builds Params for HostGroupsGet.
+type HostGroupQueryBuilder struct {
+	q query
+}
+
+// Starts building Params for HostGroupsGet.
+func HostGroupQuery() *HostGroupQueryBuilder {
+	return &HostGroupQueryBuilder{query{}}
+}
+
+// Returns only host groups with given Ids.
+func (b *HostGroupQueryBuilder) Ids(ids ...string) *HostGroupQueryBuilder {
+	b.q.ids("groupids", ids)
+	return b
+}
+
+// Returns only host groups containing given hosts.
+func (b *HostGroupQueryBuilder) WithHosts(hostIds ...string) *HostGroupQueryBuilder {
+	b.q.ids("hostids", hostIds)
+	return b
+}
+
+// Returns only host groups containing given templates.
+func (b *HostGroupQueryBuilder) WithTemplates(templateIds ...string) *HostGroupQueryBuilder {
+	b.q.ids("templateids", templateIds)
+	return b
+}
+
+// Returns only host groups containing hosts (not only templates).
+func (b *HostGroupQueryBuilder) RealHosts() *HostGroupQueryBuilder {
+	b.q["real_hosts"] = true
+	return b
+}
+
+// Returns only host groups containing monitored hosts.
+func (b *HostGroupQueryBuilder) WithMonitoredHosts() *HostGroupQueryBuilder {
+	b.q["monitored_hosts"] = true
+	return b
+}
+
+// Returns only host groups where field exactly matches one of values.
+func (b *HostGroupQueryBuilder) Filter(field string, values ...string) *HostGroupQueryBuilder {
+	b.q.filter(field, values)
+	return b
+}
+
+// Returns only host groups where field contains value.
+func (b *HostGroupQueryBuilder) Search(field, value string) *HostGroupQueryBuilder {
+	b.q.search(field, value)
+	return b
+}
+
+// Limits number of returned host groups.
+func (b *HostGroupQueryBuilder) Limit(limit int) *HostGroupQueryBuilder {
+	b.q["limit"] = limit
+	return b
+}
+
+// Returns only given fields instead of all.
+func (b *HostGroupQueryBuilder) Output(fields ...string) *HostGroupQueryBuilder {
+	b.q["output"] = fields
+	return b
+}
+
+// Sorts host groups by field; may be called several times.
+func (b *HostGroupQueryBuilder) SortBy(field string, desc bool) *HostGroupQueryBuilder {
+	b.q.sortBy(field, desc)
+	return b
+}
+
+// Returns built Params. Builder may be reused after that.
+func (b *HostGroupQueryBuilder) Params() Params {
+	return b.q.params()
+}
@@ -0,0 +1,79 @@
+package zabbix_test
+
+import (
+	. "."
+	"reflect"
+	"testing"
+)
+
+func TestQueryBuilders(t *testing.T) {
+	params := HostQuery().InGroups("1", "2").WithTemplates("3").Search("name", "web").Filter("host", "a").Limit(100).SelectInterfaces().Params()
+	expected := Params{
+		"groupids":         []string{"1", "2"},
+		"templateids":      []string{"3"},
+		"search":           map[string]string{"name": "web"},
+		"filter":           map[string]interface{}{"host": "a"},
+		"limit":            100,
+		"selectInterfaces": "extend",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Bad params:\n%#v\n%#v", params, expected)
+	}
+
+	params = ItemQuery().OnHosts("1").Filter("key_", "a", "b").SortBy("name", true).Params()
+	expected = Params{
+		"hostids":   []string{"1"},
+		"filter":    map[string]interface{}{"key_": []string{"a", "b"}},
+		"sortfield": []string{"name"},
+		"sortorder": []string{"DESC"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Bad params:\n%#v\n%#v", params, expected)
+	}
+}
+
+func TestQueryBuilderReuse(t *testing.T) {
+	b := ItemQuery().Filter("key_", "a").Search("name", "cpu").SortBy("name", false)
+	first := b.Params()
+	expected := Params{
+		"filter":    map[string]interface{}{"key_": "a"},
+		"search":    map[string]string{"name": "cpu"},
+		"sortfield": []string{"name"},
+		"sortorder": []string{"ASC"},
+	}
+
+	second := b.Filter("key_", "b", "c").Filter("status", "0").Search("name", "memory").SortBy("key_", true).Params()
+	if !reflect.DeepEqual(first, expected) {
+		t.Errorf("First params changed:\n%#v\n%#v", first, expected)
+	}
+	if !reflect.DeepEqual(second["sortfield"], []string{"name", "key_"}) || !reflect.DeepEqual(second["sortorder"], []string{"ASC", "DESC"}) ||
+		second["search"].(map[string]string)["name"] != "memory" {
+		t.Errorf("Bad second params: %#v", second)
+	}
+}
+
+func TestHostQuery(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	hosts, err := api.HostsGet(HostQuery().InGroups(group.GroupId).Filter("host", host.Host).SelectInterfaces().Params())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 || hosts[0].HostId != host.HostId || len(hosts[0].Interfaces) != 1 {
+		t.Errorf("Bad hosts: %#v", hosts)
+	}
+
+	groups, err := api.HostGroupsGet(HostGroupQuery().WithHosts(host.HostId).Params())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].GroupId != group.GroupId {
+		t.Errorf("Bad groups: %#v", groups)
+	}
+}
@@ -1,57 +1,325 @@
 package zabbix
 
 import (
-//        "encoding/csv"
-//        "fmt"
-//      "strconv"
-//       "log"
-//        "strings"
-//        "os"
-//        "github.com/AlekSi/zabbix"
-//      "flag"
+	"encoding/json"
 )
 
+type (
+	ScreenResourceType int
+	HAlignType         int
+	VAlignType         int
+)
 
-func (api *API) GetScreenElem(screenName string, params Params) (screenItems []string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("screen.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _, i := range result {
-                tmp := i.(map[string]interface{})
-                if tmp["name"].(string) == screenName {
-                        tmp2 := tmp["screenitems"].([]interface{})
-                        for _,j := range tmp2 {
-                                k := j.(map[string]interface{})
-                                if k["resourcetype"] == "0" {
-                                        item := k["resourceid"].(string)
-                                        screenItems = append(screenItems, item)
-                                }
-                        }
-                   }
-        }
-        return
+const (
+	ScreenGraph                ScreenResourceType = 0
+	ScreenSimpleGraph          ScreenResourceType = 1
+	ScreenMap                  ScreenResourceType = 2
+	ScreenPlainText            ScreenResourceType = 3
+	ScreenHostsInfo            ScreenResourceType = 4
+	ScreenTriggersInfo         ScreenResourceType = 5
+	ScreenServerInfo           ScreenResourceType = 6
+	ScreenClock                ScreenResourceType = 7
+	ScreenScreen               ScreenResourceType = 8
+	ScreenTriggersOverview     ScreenResourceType = 9
+	ScreenDataOverview         ScreenResourceType = 10
+	ScreenURL                  ScreenResourceType = 11
+	ScreenHistoryOfActions     ScreenResourceType = 12
+	ScreenHistoryOfEvents      ScreenResourceType = 13
+	ScreenHostGroupIssues      ScreenResourceType = 14
+	ScreenSystemStatus         ScreenResourceType = 15
+	ScreenHostIssues           ScreenResourceType = 16
+	ScreenSimpleGraphPrototype ScreenResourceType = 19 // Zabbix 2.4+
+	ScreenGraphPrototype       ScreenResourceType = 20 // Zabbix 2.4+
+
+	HAlignCenter HAlignType = 0
+	HAlignLeft   HAlignType = 1
+	HAlignRight  HAlignType = 2
+
+	VAlignMiddle VAlignType = 0
+	VAlignTop    VAlignType = 1
+	VAlignBottom VAlignType = 2
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/screen/definitions
+type Screen struct {
+	ScreenId    string      `json:"screenid,omitempty"`
+	Name        string      `json:"name"`
+	HSize       int         `json:"hsize"`
+	VSize       int         `json:"vsize"`
+	ScreenItems ScreenItems `json:"screenitems,omitempty"`
+}
+
+// Sends ScreenItems even if there are none unless they are nil, so all screen items can be removed on update.
+func (s Screen) MarshalJSON() ([]byte, error) {
+	type plain Screen
+	if s.ScreenItems == nil || len(s.ScreenItems) > 0 {
+		return json.Marshal(plain(s))
+	}
+	return json.Marshal(struct {
+		plain
+		ScreenItems ScreenItems `json:"screenitems"`
+	}{plain(s), s.ScreenItems})
+}
+
+type Screens []Screen
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/screenitem/definitions
+// Meaning of ResourceId depends on ResourceType: graph, item, map, screen or host group id.
+type ScreenItem struct {
+	ScreenItemId string             `json:"screenitemid,omitempty"`
+	ScreenId     string             `json:"screenid,omitempty"`
+	ResourceType ScreenResourceType `json:"resourcetype"`
+	ResourceId   string             `json:"resourceid,omitempty"`
+	X            int                `json:"x"`
+	Y            int                `json:"y"`
+	ColSpan      int                `json:"colspan,omitempty"`
+	RowSpan      int                `json:"rowspan,omitempty"`
+	Width        int                `json:"width,omitempty"`
+	Height       int                `json:"height,omitempty"`
+	HAlign       HAlignType         `json:"halign,omitempty"`
+	VAlign       VAlignType         `json:"valign,omitempty"`
+	Style        int                `json:"style,omitempty"`
+	Elements     int                `json:"elements,omitempty"`
+	SortTriggers int                `json:"sort_triggers,omitempty"`
+	Dynamic      int                `json:"dynamic,omitempty"`
+	Url          string             `json:"url,omitempty"`
+	MaxColumns   int                `json:"max_columns,omitempty"` // Zabbix 2.4+
+	Application  string             `json:"application,omitempty"` // Zabbix 3.0+
+}
+
+type ScreenItems []ScreenItem
+
+// Returns screen items with given resource type.
+func (items ScreenItems) ByResourceType(resourceType ScreenResourceType) (res ScreenItems) {
+	for _, item := range items {
+		if item.ResourceType == resourceType {
+			res = append(res, item)
+		}
+	}
+	return
 }
 
+// Wrapper for screen.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screen/get
+// Screen items are requested too.
+func (api *API) ScreensGet(params Params) (res Screens, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectScreenItems"]; !present {
+		params["selectScreenItems"] = "extend"
+	}
+	response, err := api.CallWithError("screen.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets screen by Id only if there is exactly 1 matching screen.
+func (api *API) ScreenGetById(id string) (res *Screen, err error) {
+	screens, err := api.ScreensGet(Params{"screenids": id})
+	if err != nil {
+		return
+	}
+
+	if len(screens) == 1 {
+		res = &screens[0]
+	} else {
+		e := ExpectedOneResult(len(screens))
+		err = &e
+	}
+	return
+}
+
+// Gets screen by name only if there is exactly 1 matching screen. Screens are filtered by server.
+func (api *API) ScreenGetByName(name string) (res *Screen, err error) {
+	screens, err := api.ScreensGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(screens) == 1 {
+		res = &screens[0]
+	} else {
+		e := ExpectedOneResult(len(screens))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for screen.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screen/create
+// Fills ScreenId of screens, but not ScreenItemId of screen items.
+func (api *API) ScreensCreate(screens Screens) (err error) {
+	response, err := api.CallWithError("screen.create", screens)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	screenids := result["screenids"].([]interface{})
+	for i, id := range screenids {
+		screens[i].ScreenId = id.(string)
+	}
+	return
+}
+
+// Wrapper for screen.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screen/update
+// If ScreenItems are not nil, they replace all existing screen items.
+func (api *API) ScreensUpdate(screens Screens) (err error) {
+	response, err := api.CallWithError("screen.update", screens)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	screenids := result["screenids"].([]interface{})
+	if len(screens) != len(screenids) {
+		err = &ExpectedMore{len(screens), len(screenids)}
+	}
+	return
+}
+
+// Wrapper for screen.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screen/delete
+// Cleans ScreenId in all screens elements if call succeed.
+func (api *API) ScreensDelete(screens Screens) (err error) {
+	ids := make([]string, len(screens))
+	for i, screen := range screens {
+		ids[i] = screen.ScreenId
+	}
+
+	err = api.ScreensDeleteByIds(ids)
+	if err == nil {
+		for i := range screens {
+			screens[i].ScreenId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for screen.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screen/delete
+func (api *API) ScreensDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("screen.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	screenids := result["screenids"].([]interface{})
+	if len(ids) != len(screenids) {
+		err = &ExpectedMore{len(ids), len(screenids)}
+	}
+	return
+}
+
+// Wrapper for screenitem.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screenitem/get
+func (api *API) ScreenItemsGet(params Params) (res ScreenItems, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("screenitem.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Wrapper for screenitem.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screenitem/create
+func (api *API) ScreenItemsCreate(items ScreenItems) (err error) {
+	response, err := api.CallWithError("screenitem.create", items)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	screenitemids := result["screenitemids"].([]interface{})
+	for i, id := range screenitemids {
+		items[i].ScreenItemId = id.(string)
+	}
+	return
+}
+
+// Wrapper for screenitem.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screenitem/update
+// ScreenId is not sent as screen item can't be moved to another screen.
+func (api *API) ScreenItemsUpdate(items ScreenItems) (err error) {
+	update := make(ScreenItems, len(items))
+	for i, item := range items {
+		item.ScreenId = ""
+		update[i] = item
+	}
+
+	response, err := api.CallWithError("screenitem.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	screenitemids := result["screenitemids"].([]interface{})
+	if len(items) != len(screenitemids) {
+		err = &ExpectedMore{len(items), len(screenitemids)}
+	}
+	return
+}
+
+// Wrapper for screenitem.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screenitem/delete
+// Cleans ScreenItemId in all items elements if call succeed.
+func (api *API) ScreenItemsDelete(items ScreenItems) (err error) {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ScreenItemId
+	}
+
+	err = api.ScreenItemsDeleteByIds(ids)
+	if err == nil {
+		for i := range items {
+			items[i].ScreenItemId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for screenitem.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/screenitem/delete
+func (api *API) ScreenItemsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("screenitem.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	screenitemids := result["screenitemids"].([]interface{})
+	if len(ids) != len(screenitemids) {
+		err = &ExpectedMore{len(ids), len(screenitemids)}
+	}
+	return
+}
+
+// Returns resource ids of graph elements of screen with given name.
+func (api *API) GetScreenElem(screenName string, params Params) (screenItems []string, err error) {
+	params["filter"] = map[string]string{"name": screenName}
+	screens, err := api.ScreensGet(params)
+	if err != nil {
+		return
+	}
+	for _, screen := range screens {
+		for _, item := range screen.ScreenItems.ByResourceType(ScreenGraph) {
+			screenItems = append(screenItems, item.ResourceId)
+		}
+	}
+	return
+}
 
+// Returns id of screen with given name, or empty string if there is no such screen.
 func (api *API) CheckScreen(screenName string, params Params) (screenId string, err error) {
-        if _, present := params["output"]; !present {
-                params["output"] = "extend"
-        }
-        response, err := api.CallWithError("screen.get", params)
-        if err != nil {
-                return
-        }
-        result := response.Result.([]interface{})
-        for _, i := range result {
-                tmp := i.(map[string]interface{})
-                if tmp["name"].(string) == screenName {
-                        screenId = tmp["screenid"].(string)
-                }
-        }
-        return
+	params["filter"] = map[string]string{"name": screenName}
+	screens, err := api.ScreensGet(params)
+	if err != nil {
+		return
+	}
+	for _, screen := range screens {
+		screenId = screen.ScreenId
+	}
+	return
 }
@@ -0,0 +1,266 @@
+package zabbix
+
+import (
+	"sort"
+)
+
+// Arranges graphs row by row in a grid with given number of columns.
+// Zero values are replaced by defaults.
+type GridLayout struct {
+	Columns      int // 2 by default; dashboards use at most as many as fit in 12 grid cells
+	Width        int // screen graph width in pixels, 500 by default
+	Height       int // screen graph height in pixels, 100 by default
+	WidgetWidth  int // dashboard widget width in grid cells, 12 / Columns by default
+	WidgetHeight int // dashboard widget height in grid rows, 5 by default
+}
+
+func (l GridLayout) withDefaults() GridLayout {
+	if l.Columns <= 0 {
+		l.Columns = 2
+	}
+	if l.Width <= 0 {
+		l.Width = 500
+	}
+	if l.Height <= 0 {
+		l.Height = 100
+	}
+	if l.WidgetWidth <= 0 {
+		l.WidgetWidth = 12 / l.Columns
+		if l.WidgetWidth == 0 {
+			l.WidgetWidth = 1
+		}
+	}
+	if l.WidgetHeight <= 0 {
+		l.WidgetHeight = 5
+	}
+	return l
+}
+
+// Returns screen size in cells needed for n graphs.
+func (l GridLayout) ScreenSize(n int) (hsize, vsize int) {
+	l = l.withDefaults()
+	hsize, vsize = l.Columns, (n+l.Columns-1)/l.Columns
+	if n < l.Columns {
+		hsize = n
+	}
+	if hsize == 0 {
+		hsize, vsize = 1, 1
+	}
+	return
+}
+
+// Returns screen items for graphs, one graph per cell.
+func (l GridLayout) ScreenItems(graphIds []string) (items ScreenItems) {
+	l = l.withDefaults()
+	items = make(ScreenItems, len(graphIds))
+	for i, id := range graphIds {
+		items[i] = ScreenItem{
+			ResourceType: ScreenGraph,
+			ResourceId:   id,
+			X:            i % l.Columns,
+			Y:            i / l.Columns,
+			ColSpan:      1,
+			RowSpan:      1,
+			Width:        l.Width,
+			Height:       l.Height,
+		}
+	}
+	return
+}
+
+// Returns dashboard graph widgets for graphs.
+// Widgets are wrapped to the next row instead of going past 12 cells of dashboard grid.
+func (l GridLayout) Widgets(graphIds []string) (widgets DashboardWidgets) {
+	l = l.withDefaults()
+	if l.WidgetWidth > 12 {
+		l.WidgetWidth = 12
+	}
+	if l.Columns*l.WidgetWidth > 12 {
+		l.Columns = 12 / l.WidgetWidth
+	}
+	widgets = make(DashboardWidgets, len(graphIds))
+	for i, id := range graphIds {
+		widgets[i] = DashboardWidget{
+			Type:   WidgetGraph,
+			X:      i % l.Columns * l.WidgetWidth,
+			Y:      i / l.Columns * l.WidgetHeight,
+			Width:  l.WidgetWidth,
+			Height: l.WidgetHeight,
+			Fields: DashboardWidgetFields{{Type: WidgetFieldGraph, Name: "graphid", Value: id}},
+		}
+	}
+	return
+}
+
+// Creates screen with given name and graphs arranged by layout, or updates existing one.
+// Elements of existing screen not matching layout are removed.
+// Screen is not changed if it already has the same layout.
+func (api *API) ScreenLayoutApply(name string, layout GridLayout, graphIds []string) (screen *Screen, err error) {
+	hsize, vsize := layout.ScreenSize(len(graphIds))
+	desired := Screen{Name: name, HSize: hsize, VSize: vsize, ScreenItems: layout.ScreenItems(graphIds)}
+
+	screens, err := api.ScreensGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+	switch len(screens) {
+	case 0:
+		screens = Screens{desired}
+		err = api.ScreensCreate(screens)
+	case 1:
+		existing := screens[0]
+		if existing.HSize == hsize && existing.VSize == vsize && sameScreenItems(existing.ScreenItems, desired.ScreenItems) {
+			return &existing, nil
+		}
+		desired.ScreenId = existing.ScreenId
+		screens = Screens{desired}
+		err = api.ScreensUpdate(screens)
+	default:
+		e := ExpectedOneResult(len(screens))
+		err = &e
+	}
+	if err == nil {
+		screen = &screens[0]
+	}
+	return
+}
+
+// Same as ScreenLayoutApply for graphs with names containing given substring, sorted by name.
+func (api *API) ScreenLayoutApplyByGraphName(name string, layout GridLayout, graphName string, params Params) (screen *Screen, err error) {
+	ids, err := api.graphIdsByName(graphName, params)
+	if err != nil {
+		return
+	}
+	return api.ScreenLayoutApply(name, layout, ids)
+}
+
+// Creates dashboard with given name and graph widgets arranged by layout, or updates existing one.
+// Widgets of existing dashboard not matching layout are removed; widgets of the same graphs keep their ids.
+// Dashboard is not changed if it already has the same layout.
+func (api *API) DashboardLayoutApply(name string, layout GridLayout, graphIds []string) (dashboard *Dashboard, err error) {
+	desired := Dashboard{Name: name, Widgets: layout.Widgets(graphIds)}
+
+	dashboards, err := api.DashboardsGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+	switch len(dashboards) {
+	case 0:
+		dashboards = Dashboards{desired}
+		err = api.DashboardsCreate(dashboards)
+	case 1:
+		existing := dashboards[0]
+		if sameGraphWidgets(existing.Widgets, desired.Widgets) {
+			return &existing, nil
+		}
+		ids := make(map[string]string, len(existing.Widgets))
+		for _, w := range existing.Widgets {
+			if graphId, found := w.Field("graphid"); found && w.Type == WidgetGraph {
+				ids[graphId] = w.WidgetId
+			}
+		}
+		for i, graphId := range graphIds {
+			desired.Widgets[i].WidgetId = ids[graphId]
+			delete(ids, graphId)
+		}
+		desired.DashboardId, desired.Private = existing.DashboardId, existing.Private
+		dashboards = Dashboards{desired}
+		err = api.DashboardsUpdate(dashboards)
+	default:
+		e := ExpectedOneResult(len(dashboards))
+		err = &e
+	}
+	if err == nil {
+		dashboard = &dashboards[0]
+	}
+	return
+}
+
+// Same as DashboardLayoutApply for graphs with names containing given substring, sorted by name.
+func (api *API) DashboardLayoutApplyByGraphName(name string, layout GridLayout, graphName string, params Params) (dashboard *Dashboard, err error) {
+	ids, err := api.graphIdsByName(graphName, params)
+	if err != nil {
+		return
+	}
+	return api.DashboardLayoutApply(name, layout, ids)
+}
+
+func (api *API) graphIdsByName(graphName string, params Params) (ids []string, err error) {
+	if _, present := params["sortfield"]; !present {
+		params["sortfield"] = "name"
+	}
+	return api.GraphGet(graphName, params)
+}
+
+type screenCell struct {
+	resourceType           ScreenResourceType
+	resourceId             string
+	x, y, colSpan, rowSpan int
+	width, height          int
+}
+
+func sameScreenItems(a, b ScreenItems) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	cells := func(items ScreenItems) []screenCell {
+		res := make([]screenCell, len(items))
+		for i, item := range items {
+			res[i] = screenCell{item.ResourceType, item.ResourceId, item.X, item.Y, item.ColSpan, item.RowSpan, item.Width, item.Height}
+			// older versions return 0 for default span of one cell
+			if res[i].colSpan == 0 {
+				res[i].colSpan = 1
+			}
+			if res[i].rowSpan == 0 {
+				res[i].rowSpan = 1
+			}
+		}
+		sort.Slice(res, func(i, j int) bool {
+			if res[i].y != res[j].y {
+				return res[i].y < res[j].y
+			}
+			return res[i].x < res[j].x
+		})
+		return res
+	}
+	ca, cb := cells(a), cells(b)
+	for i := range ca {
+		if ca[i] != cb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type widgetCell struct {
+	widgetType          string
+	graphId             string
+	x, y, width, height int
+}
+
+func sameGraphWidgets(a, b DashboardWidgets) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	cells := func(widgets DashboardWidgets) []widgetCell {
+		res := make([]widgetCell, len(widgets))
+		for i, w := range widgets {
+			graphId, _ := w.Field("graphid")
+			res[i] = widgetCell{w.Type, graphId, w.X, w.Y, w.Width, w.Height}
+		}
+		sort.Slice(res, func(i, j int) bool {
+			if res[i].y != res[j].y {
+				return res[i].y < res[j].y
+			}
+			return res[i].x < res[j].x
+		})
+		return res
+	}
+	ca, cb := cells(a), cells(b)
+	for i := range ca {
+		if ca[i] != cb[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGridLayout(t *testing.T) {
+	layout := GridLayout{Columns: 3, Width: 400}
+	items := layout.ScreenItems([]string{"1", "2", "3", "4"})
+	if len(items) != 4 {
+		t.Fatalf("Expected 4 items, got %#v", items)
+	}
+	last := items[3]
+	if last.ResourceId != "4" || last.X != 0 || last.Y != 1 || last.Width != 400 || last.Height != 100 || last.ResourceType != ScreenGraph {
+		t.Errorf("Bad screen item: %#v", last)
+	}
+	if h, v := layout.ScreenSize(4); h != 3 || v != 2 {
+		t.Errorf("Bad screen size %dx%d", h, v)
+	}
+	if h, v := layout.ScreenSize(2); h != 2 || v != 1 {
+		t.Errorf("Bad screen size %dx%d", h, v)
+	}
+
+	widgets := layout.Widgets([]string{"1", "2", "3", "4"})
+	w := widgets[2]
+	if id, _ := w.Field("graphid"); id != "3" || w.X != 8 || w.Y != 0 || w.Width != 4 || w.Height != 5 {
+		t.Errorf("Bad widget: %#v", w)
+	}
+	if w = widgets[3]; w.X != 0 || w.Y != 5 {
+		t.Errorf("Bad widget: %#v", w)
+	}
+
+	// dashboard grid is 12 cells wide
+	ids := make([]string, 14)
+	widgets = GridLayout{Columns: 20}.Widgets(ids)
+	if w = widgets[11]; w.X != 11 || w.Y != 0 || w.Width != 1 {
+		t.Errorf("Bad widget: %#v", w)
+	}
+	if w = widgets[13]; w.X != 1 || w.Y != 5 {
+		t.Errorf("Bad widget: %#v", w)
+	}
+	widgets = GridLayout{Columns: 3, WidgetWidth: 5}.Widgets(ids)
+	if w = widgets[2]; w.X != 0 || w.Y != 5 || w.Width != 5 {
+		t.Errorf("Bad widget: %#v", w)
+	}
+
+	// layout without graphs removes all elements on update
+	b, err := json.Marshal(Screen{ScreenId: "1", Name: "empty", ScreenItems: layout.ScreenItems(nil)})
+	if err != nil || !strings.Contains(string(b), `"screenitems":[]`) {
+		t.Errorf("Bad screen: %s, %v", b, err)
+	}
+	b, err = json.Marshal(Dashboard{DashboardId: "1", Name: "empty", Widgets: layout.Widgets(nil)})
+	if err != nil || !strings.Contains(string(b), `"widgets":[]`) || strings.Contains(string(b), "private") {
+		t.Errorf("Bad dashboard: %s, %v", b, err)
+	}
+	b, err = json.Marshal(Screen{ScreenId: "1", Name: "unchanged"})
+	if err != nil || strings.Contains(string(b), "screenitems") {
+		t.Errorf("Bad screen: %s, %v", b, err)
+	}
+}
+
+func TestScreenLayoutApply(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	graph := CreateGraph(host, t)
+	defer DeleteGraph(graph, t)
+
+	clock := Screens{{Name: "lala layout", HSize: 1, VSize: 1, ScreenItems: ScreenItems{{ResourceType: ScreenClock}}}}
+	err := api.ScreensCreate(clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer api.ScreensDeleteByIds([]string{clock[0].ScreenId})
+
+	screen, err := api.ScreenLayoutApplyByGraphName("lala layout", GridLayout{Columns: 1}, "Traffic", Params{"hostids": host.HostId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if screen.ScreenId != clock[0].ScreenId {
+		t.Errorf("Screen was not reused: %#v", screen)
+	}
+
+	screen, err = api.ScreenGetById(screen.ScreenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(screen.ScreenItems) != 1 || screen.ScreenItems[0].ResourceId != graph.Graphid {
+		t.Errorf("Bad screen items: %#v", screen.ScreenItems)
+	}
+	itemId := screen.ScreenItems[0].ScreenItemId
+
+	screen, err = api.ScreenLayoutApply("lala layout", GridLayout{Columns: 1}, []string{graph.Graphid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if screen.ScreenItems[0].ScreenItemId != itemId {
+		t.Errorf("Screen with the same layout was changed: %#v", screen.ScreenItems)
+	}
+
+	_, err = api.ScreenLayoutApply("lala layout", GridLayout{Columns: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen, err = api.ScreenGetById(screen.ScreenId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(screen.ScreenItems) != 0 {
+		t.Errorf("Stale screen items: %#v", screen.ScreenItems)
+	}
+}
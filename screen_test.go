@@ -0,0 +1,190 @@
+package zabbix_test
+
+import (
+	. "."
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Skips test if Zabbix server is older than major.minor.
+func requireVersion(t *testing.T, major, minor int) {
+	v, err := getAPI(t).Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(v, ".")
+	ma, _ := strconv.Atoi(parts[0])
+	mi, _ := strconv.Atoi(parts[1])
+	if ma < major || (ma == major && mi < minor) {
+		t.Skipf("Zabbix %d.%d+ is required, got %s", major, minor, v)
+	}
+}
+
+func TestScreens(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	graph := CreateGraph(host, t)
+	defer DeleteGraph(graph, t)
+
+	screens := Screens{{
+		Name:  "lala screen",
+		HSize: 2,
+		VSize: 1,
+		ScreenItems: ScreenItems{
+			{ResourceType: ScreenGraph, ResourceId: graph.Graphid, Width: 500, Height: 100},
+			{ResourceType: ScreenClock, X: 1, Style: 1},
+		},
+	}}
+	err := api.ScreensCreate(screens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := &screens[0]
+	if screen.ScreenId == "" {
+		t.Errorf("Id is empty: %#v", screen)
+	}
+
+	screen2, err := api.ScreenGetByName("lala screen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if screen2.ScreenId != screen.ScreenId || screen2.HSize != 2 || len(screen2.ScreenItems) != 2 {
+		t.Errorf("Bad screen: %#v", screen2)
+	}
+	graphs := screen2.ScreenItems.ByResourceType(ScreenGraph)
+	if len(graphs) != 1 || graphs[0].ResourceId != graph.Graphid || graphs[0].Width != 500 {
+		t.Errorf("Bad screen items: %#v", screen2.ScreenItems)
+	}
+
+	ids, err := api.GetScreenElem("lala screen", Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != graph.Graphid {
+		t.Errorf("Bad screen elements: %v", ids)
+	}
+	id, err := api.CheckScreen("lala screen", Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != screen.ScreenId {
+		t.Errorf("Bad screen id %s, expected %s", id, screen.ScreenId)
+	}
+
+	screen.HSize = 3
+	screen.ScreenItems = nil
+	err = api.ScreensUpdate(screens)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := ScreenItems{{ScreenId: screen.ScreenId, ResourceType: ScreenURL, X: 2, Url: "http://example.com/"}}
+	err = api.ScreenItemsCreate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].ScreenItemId == "" {
+		t.Errorf("Id is empty: %#v", items[0])
+	}
+	items[0].Width = 640
+	err = api.ScreenItemsUpdate(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items2, err := api.ScreenItemsGet(Params{"screenids": screen.ScreenId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items2) != 3 {
+		t.Errorf("Expected 3 screen items, got %#v", items2)
+	}
+
+	err = api.ScreenItemsDelete(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].ScreenItemId != "" {
+		t.Errorf("Id is not empty: %#v", items[0])
+	}
+
+	err = api.ScreensDelete(screens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if screen.ScreenId != "" {
+		t.Errorf("Id is not empty: %#v", screen)
+	}
+}
+
+func TestDashboards(t *testing.T) {
+	requireVersion(t, 4, 0)
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	graph := CreateGraph(host, t)
+	defer DeleteGraph(graph, t)
+
+	dashboards := Dashboards{{
+		Name: "lala dashboard",
+		Widgets: DashboardWidgets{{
+			Type:   WidgetGraph,
+			Width:  6,
+			Height: 5,
+			Fields: DashboardWidgetFields{{Type: WidgetFieldGraph, Name: "graphid", Value: graph.Graphid}},
+		}},
+	}}
+	err := api.DashboardsCreate(dashboards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboard := &dashboards[0]
+	if dashboard.DashboardId == "" {
+		t.Errorf("Id is empty: %#v", dashboard)
+	}
+
+	dashboard2, err := api.DashboardGetByName("lala dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dashboard2.DashboardId != dashboard.DashboardId || len(dashboard2.Widgets) != 1 {
+		t.Fatalf("Bad dashboard: %#v", dashboard2)
+	}
+	widget := dashboard2.Widgets[0]
+	if id, _ := widget.Field("graphid"); id != graph.Graphid || widget.Width != 6 || widget.WidgetId == "" {
+		t.Errorf("Bad widget: %#v", widget)
+	}
+
+	dashboard2.Widgets = append(dashboard2.Widgets, DashboardWidget{Type: WidgetClock, X: 6, Width: 4, Height: 3})
+	err = api.DashboardsUpdate(Dashboards{*dashboard2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboard3, err := api.DashboardGetById(dashboard.DashboardId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dashboard3.Widgets) != 2 {
+		t.Errorf("Expected 2 widgets, got %#v", dashboard3.Widgets)
+	}
+
+	err = api.DashboardsDelete(dashboards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.DashboardId != "" {
+		t.Errorf("Id is not empty: %#v", dashboard)
+	}
+}
@@ -0,0 +1,77 @@
+package sender
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var macroRE = regexp.MustCompile(`^\{#[A-Z0-9_.]+\}$`)
+
+type InvalidMacro string
+
+func (e InvalidMacro) Error() string {
+	return fmt.Sprintf("Invalid LLD macro name %q, expected {#UPPER_CASE}.", string(e))
+}
+
+// Low-level discovery data for trapper discovery rules, like {"data":[{"{#IFNAME}":"eth0"}]}.
+type Discovery struct {
+	rows []map[string]string
+}
+
+// Adds discovered entity with LLD macros values, like {"{#IFNAME}": "eth0"}.
+func (d *Discovery) Add(macros map[string]string) (err error) {
+	row := make(map[string]string, len(macros))
+	for macro, value := range macros {
+		if !macroRE.MatchString(macro) {
+			return InvalidMacro(macro)
+		}
+		row[macro] = value
+	}
+	d.rows = append(d.rows, row)
+	return
+}
+
+// Returns number of added entities.
+func (d *Discovery) Len() int {
+	return len(d.rows)
+}
+
+// Returns true if server of given version (like "4.0.5") expects data wrapped in {"data":[...]} object.
+// Zabbix 4.2+ also accepts bare array.
+func LegacyDiscoveryFormat(version string) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return true
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return true
+	}
+	return major < 4 || major == 4 && minor < 2
+}
+
+// Marshals data in format suitable for server of given version.
+func (d *Discovery) JSON(version string) (b []byte, err error) {
+	rows := d.rows
+	if rows == nil {
+		rows = []map[string]string{}
+	}
+	if LegacyDiscoveryFormat(version) {
+		return json.Marshal(map[string]interface{}{"data": rows})
+	}
+	return json.Marshal(rows)
+}
+
+// Returns value for discovery rule key of host, in format suitable for server of given version.
+func (d *Discovery) Value(host, key, version string) (v Value, err error) {
+	b, err := d.JSON(version)
+	if err != nil {
+		return
+	}
+	v = Value{Host: host, Key: key, Value: string(b)}
+	return
+}
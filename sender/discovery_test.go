@@ -0,0 +1,44 @@
+package sender_test
+
+import (
+	. "."
+	"testing"
+)
+
+func TestDiscovery(t *testing.T) {
+	var d Discovery
+	if err := d.Add(map[string]string{"{#IFNAME}": "eth0", "{#IFALIAS}": "uplink"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Add(map[string]string{"{#IFNAME}": "eth1"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, macro := range []string{"{#ifname}", "{$IFNAME}", "IFNAME", "{#}", "{#IF NAME}"} {
+		if err := d.Add(map[string]string{macro: "eth2"}); err == nil {
+			t.Errorf("%s: expected error", macro)
+		}
+	}
+	if d.Len() != 2 {
+		t.Errorf("Bad length: %d", d.Len())
+	}
+
+	for version, expected := range map[string]string{
+		"2.0.9": `{"data":[{"{#IFALIAS}":"uplink","{#IFNAME}":"eth0"},{"{#IFNAME}":"eth1"}]}`,
+		"4.0.5": `{"data":[{"{#IFALIAS}":"uplink","{#IFNAME}":"eth0"},{"{#IFNAME}":"eth1"}]}`,
+		"4.2.0": `[{"{#IFALIAS}":"uplink","{#IFNAME}":"eth0"},{"{#IFNAME}":"eth1"}]`,
+		"5.0.1": `[{"{#IFALIAS}":"uplink","{#IFNAME}":"eth0"},{"{#IFNAME}":"eth1"}]`,
+	} {
+		v, err := d.Value("switch", "net.if.discovery", version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Host != "switch" || v.Key != "net.if.discovery" || v.Value != expected {
+			t.Errorf("%s: bad value %#v", version, v)
+		}
+	}
+
+	b, _ := new(Discovery).JSON("5.0.0")
+	if string(b) != "[]" {
+		t.Errorf("Bad empty data: %s", b)
+	}
+}
@@ -0,0 +1,156 @@
+// Package sender implements Zabbix sender protocol, pushing values to trapper items
+// (items of type zabbix.ZabbixTrapper) like zabbix_sender does.
+//
+// See https://www.zabbix.com/documentation/current/manual/appendix/protocols/zabbix_sender
+package sender
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+const (
+	// Values per packet by default, as in zabbix_sender.
+	DefaultBatchSize = 250
+
+	DefaultTimeout = 60 * time.Second
+)
+
+// Single value for trapper item.
+type Value struct {
+	Host  string
+	Key   string
+	Value string
+	Time  time.Time // zero time means the time server receives value
+}
+
+// Summary of values processing returned by server.
+type Result struct {
+	Processed int
+	Failed    int
+	Total     int
+	Spent     time.Duration
+}
+
+// Error returned when server replies with anything but success.
+type Failed struct {
+	Response string
+	Info     string
+}
+
+func (e *Failed) Error() string {
+	return fmt.Sprintf("Server response %q: %s", e.Response, e.Info)
+}
+
+type Sender struct {
+	Addr      string        // server or proxy address like "zabbix:10051"
+	Timeout   time.Duration // for each packet exchange, zero means no timeout
+	BatchSize int           // values per packet
+	Compress  bool          // compress packets with zlib, Zabbix 4.0+
+}
+
+type value struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Clock int64  `json:"clock,omitempty"`
+	Ns    int    `json:"ns,omitempty"`
+}
+
+type request struct {
+	Request string  `json:"request"`
+	Data    []value `json:"data"`
+	Clock   int64   `json:"clock,omitempty"`
+	Ns      int     `json:"ns,omitempty"`
+}
+
+// Server reply to sender and agent data requests.
+type Response struct {
+	Response string `json:"response"`
+	Info     string `json:"info"`
+}
+
+// Creates new sender with default batch size and timeout.
+func NewSender(addr string) *Sender {
+	return &Sender{Addr: addr, Timeout: DefaultTimeout, BatchSize: DefaultBatchSize}
+}
+
+// Sends values in batches of s.BatchSize, returning summary for all sent batches.
+// Stops on first error.
+func (s *Sender) Send(values []Value) (res Result, err error) {
+	size := s.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	for start := 0; start < len(values); start += size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+
+		var r Result
+		r, err = s.send(values[start:end])
+		res.Processed += r.Processed
+		res.Failed += r.Failed
+		res.Total += r.Total
+		res.Spent += r.Spent
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (s *Sender) send(values []Value) (res Result, err error) {
+	req := request{Request: "sender data", Data: make([]value, len(values))}
+	timestamps := false
+	for i, v := range values {
+		req.Data[i] = value{Host: v.Host, Key: v.Key, Value: v.Value}
+		if !v.Time.IsZero() {
+			req.Data[i].Clock = v.Time.Unix()
+			req.Data[i].Ns = v.Time.Nanosecond()
+			timestamps = true
+		}
+	}
+	if timestamps {
+		// lets server correct clock difference
+		now := time.Now()
+		req.Clock = now.Unix()
+		req.Ns = now.Nanosecond()
+	}
+
+	var response Response
+	err = protocol.Exchange(s.Addr, s.Timeout, s.Compress, req, &response)
+	if err != nil {
+		return
+	}
+	return response.Result()
+}
+
+// Zabbix before 2.2 used "Processed 1 Failed 0 Total 1 Seconds spent 0.000055" format.
+var infoRE = regexp.MustCompile(`(?i)processed:? (\d+);? failed:? (\d+);? total:? (\d+);? seconds spent:? ([\d.]+)`)
+
+// Parses Info of successful response like "processed: 1; failed: 0; total: 1; seconds spent: 0.000055".
+func (r *Response) Result() (res Result, err error) {
+	if r.Response != "success" {
+		err = &Failed{r.Response, r.Info}
+		return
+	}
+
+	m := infoRE.FindStringSubmatch(r.Info)
+	if m == nil {
+		err = fmt.Errorf("Unexpected server info %q.", r.Info)
+		return
+	}
+	res.Processed, _ = strconv.Atoi(m[1])
+	res.Failed, _ = strconv.Atoi(m[2])
+	res.Total, _ = strconv.Atoi(m[3])
+	spent, _ := strconv.ParseFloat(m[4], 64)
+	res.Spent = time.Duration(spent * float64(time.Second))
+	return
+}
@@ -0,0 +1,116 @@
+package sender_test
+
+import (
+	. "."
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AlekSi/zabbix/protocol"
+)
+
+type request struct {
+	Request string `json:"request"`
+	Data    []struct {
+		Host  string `json:"host"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Clock int64  `json:"clock"`
+		Ns    int    `json:"ns"`
+	} `json:"data"`
+	Clock int64 `json:"clock"`
+}
+
+// Starts local stand-in for Zabbix trapper, which accepts values with "fail" value as failed.
+func serveTrapper(t *testing.T) (addr string, requests chan request) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests = make(chan request, 10)
+	go func() {
+		defer l.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := protocol.Read(conn, protocol.MaxDataSize)
+			if err != nil {
+				t.Error(err)
+				conn.Close()
+				continue
+			}
+			var req request
+			json.Unmarshal(b, &req)
+			requests <- req
+
+			failed := 0
+			for _, d := range req.Data {
+				if d.Value == "fail" {
+					failed++
+				}
+			}
+			info := fmt.Sprintf("processed: %d; failed: %d; total: %d; seconds spent: 0.000055", len(req.Data)-failed, failed, len(req.Data))
+			b, _ = json.Marshal(map[string]string{"response": "success", "info": info})
+			protocol.Write(conn, b, false)
+			conn.Close()
+		}
+	}()
+	return l.Addr().String(), requests
+}
+
+func TestSend(t *testing.T) {
+	addr, requests := serveTrapper(t)
+
+	for _, compress := range []bool{false, true} {
+		s := NewSender(addr)
+		s.BatchSize = 2
+		s.Compress = compress
+		ts := time.Unix(1400000000, 42)
+		values := []Value{
+			{Host: "host", Key: "key1", Value: "1", Time: ts},
+			{Host: "host", Key: "key2", Value: "fail"},
+			{Host: "host", Key: "key3", Value: "3"},
+		}
+
+		res, err := s.Send(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := Result{Processed: 2, Failed: 1, Total: 3, Spent: 110 * time.Microsecond}
+		if res != expected {
+			t.Errorf("Bad result: %#v", res)
+		}
+
+		req := <-requests
+		if req.Request != "sender data" || len(req.Data) != 2 {
+			t.Errorf("Bad request: %#v", req)
+		}
+		if req.Data[0].Clock != 1400000000 || req.Data[0].Ns != 42 || req.Clock == 0 {
+			t.Errorf("Bad timestamps: %#v", req)
+		}
+		req = <-requests
+		if len(req.Data) != 1 || req.Data[0].Key != "key3" || req.Data[0].Clock != 0 || req.Clock != 0 {
+			t.Errorf("Bad request: %#v", req)
+		}
+	}
+}
+
+func TestResponseResult(t *testing.T) {
+	r := Response{Response: "failed", Info: "host not found"}
+	if _, err := r.Result(); err == nil {
+		t.Error("Expected error")
+	}
+
+	r = Response{Response: "success", Info: "Processed 1 Failed 2 Total 3 Seconds spent 0.5"}
+	res, err := r.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (Result{Processed: 1, Failed: 2, Total: 3, Spent: 500 * time.Millisecond}) {
+		t.Errorf("Bad result: %#v", res)
+	}
+}
@@ -0,0 +1,49 @@
+// Package state loads desired state of Zabbix configuration from YAML or JSON files
+// and checks names used in them, so it can be passed to zabbix.API.SyncPlan.
+//
+// Objects are referenced by names instead of ids. Example of YAML file
+// (JSON files use the same field names):
+//
+//	host_groups:                       # managed host groups, created if missing
+//	  - Linux servers
+//	  - Templates
+//	templates:                         # managed templates, created if missing
+//	  - template: Template Web         # technical name, required
+//	    name: Web server               # visible name
+//	    description: Checks of web servers
+//	    groups: [Templates]            # declared in host_groups or existing
+//	hosts:
+//	  - host: web1                     # technical name, required
+//	    name: Web server 1             # visible name
+//	    status: monitored              # monitored (default) or disabled
+//	    description: Front web server
+//	    groups: [Linux servers]        # declared in host_groups or existing
+//	    templates: [Template Web]      # declared in templates or existing, by technical name
+//	    interfaces:
+//	      - type: agent                # agent, snmp, ipmi or jmx
+//	        ip: 192.0.2.10
+//	        dns: web1.example.com
+//	        port: "10050"              # default port of interface type if omitted
+//	        main: true                 # first interface of each type by default
+//	        useip: true                # true if ip is set by default
+//	    applications: [CPU]
+//	    items:
+//	      - key_: system.cpu.load
+//	        name: CPU load
+//	        type: zabbix_agent         # see below
+//	        value_type: float          # float, character, log, unsigned or text
+//	        delay: 1m                  # number is seconds
+//	        history: 7d                # number is days before Zabbix 3.4, seconds since
+//	        trends: 365d
+//	        description: Load average
+//	        applications: [CPU]        # declared for the same host or existing on it
+//
+// Item types are zabbix_agent, snmpv1, zabbix_trapper, simple, snmpv2, internal, snmpv3,
+// zabbix_agent_active, aggregate, web, external, database, ipmi, ssh, telnet, calculated and jmx.
+// Numeric values of zabbix constants are accepted for all enumerations too.
+//
+// Templates are created and updated, but their items and other contents are not managed;
+// host groups and templates are never deleted.
+// Unknown fields, missing required fields, duplicates and unresolved references are reported
+// with file and line positions.
+package state
@@ -0,0 +1,394 @@
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/AlekSi/zabbix"
+	"gopkg.in/yaml.v3"
+)
+
+// Error at given position of file.
+type Error struct {
+	File    string
+	Line    int // 0 if position is unknown
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// All errors found in file, ordered by position.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// String with position in file.
+type Name struct {
+	Value  string
+	Line   int
+	Column int
+}
+
+func (n *Name) UnmarshalYAML(node *yaml.Node) error {
+	n.Line, n.Column = node.Line, node.Column
+	if node.Kind != yaml.ScalarNode {
+		return nodeError(node, "expected string")
+	}
+	n.Value = node.Value
+	return nil
+}
+
+// Desired state file. See package documentation for format.
+type File struct {
+	Path       string     `yaml:"-"`
+	HostGroups []Name     `yaml:"host_groups"`
+	Templates  []Template `yaml:"templates"`
+	Hosts      []Host     `yaml:"hosts"`
+}
+
+type Template struct {
+	Template    Name   `yaml:"template"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Groups      []Name `yaml:"groups"`
+
+	Line   int `yaml:"-"`
+	Column int `yaml:"-"`
+}
+
+type Host struct {
+	Host         Name        `yaml:"host"`
+	Name         string      `yaml:"name"`
+	Status       Status      `yaml:"status"`
+	Description  string      `yaml:"description"`
+	Groups       []Name      `yaml:"groups"`
+	Templates    []Name      `yaml:"templates"`
+	Interfaces   []Interface `yaml:"interfaces"`
+	Applications []Name      `yaml:"applications"`
+	Items        []Item      `yaml:"items"`
+
+	Line   int `yaml:"-"`
+	Column int `yaml:"-"`
+}
+
+type Interface struct {
+	Type  InterfaceType `yaml:"type"`
+	IP    string        `yaml:"ip"`
+	DNS   string        `yaml:"dns"`
+	Port  string        `yaml:"port"`
+	Main  *bool         `yaml:"main"`
+	UseIP *bool         `yaml:"useip"`
+}
+
+type Item struct {
+	Key          Name      `yaml:"key_"`
+	Name         string    `yaml:"name"`
+	Type         ItemType  `yaml:"type"`
+	ValueType    ValueType `yaml:"value_type"`
+	Delay        string    `yaml:"delay"`
+	History      string    `yaml:"history"`
+	Trends       string    `yaml:"trends"`
+	Description  string    `yaml:"description"`
+	Applications []Name    `yaml:"applications"`
+
+	Line   int `yaml:"-"`
+	Column int `yaml:"-"`
+}
+
+type (
+	Status        zabbix.StatusType
+	InterfaceType zabbix.InterfaceType
+	ItemType      zabbix.ItemType
+	ValueType     zabbix.ValueType
+)
+
+var (
+	statuses = map[string]int{
+		"monitored": int(zabbix.Monitored),
+		"disabled":  int(zabbix.Unmonitored),
+	}
+	interfaceTypes = map[string]int{
+		"agent": int(zabbix.Agent),
+		"snmp":  int(zabbix.SNMP),
+		"ipmi":  int(zabbix.IPMI),
+		"jmx":   int(zabbix.JMX),
+	}
+	itemTypes = map[string]int{
+		"zabbix_agent":        int(zabbix.ZabbixAgent),
+		"snmpv1":              int(zabbix.SNMPv1Agent),
+		"zabbix_trapper":      int(zabbix.ZabbixTrapper),
+		"simple":              int(zabbix.SimpleCheck),
+		"snmpv2":              int(zabbix.SNMPv2Agent),
+		"internal":            int(zabbix.ZabbixInternal),
+		"snmpv3":              int(zabbix.SNMPv3Agent),
+		"zabbix_agent_active": int(zabbix.ZabbixAgentActive),
+		"aggregate":           int(zabbix.ZabbixAggregate),
+		"web":                 int(zabbix.WebItem),
+		"external":            int(zabbix.ExternalCheck),
+		"database":            int(zabbix.DatabaseMonitor),
+		"ipmi":                int(zabbix.IPMIAgent),
+		"ssh":                 int(zabbix.SSHAgent),
+		"telnet":              int(zabbix.TELNETAgent),
+		"calculated":          int(zabbix.Calculated),
+		"jmx":                 int(zabbix.JMXAgent),
+	}
+	valueTypes = map[string]int{
+		"float":     int(zabbix.Float),
+		"character": int(zabbix.Character),
+		"log":       int(zabbix.Log),
+		"unsigned":  int(zabbix.Unsigned),
+		"text":      int(zabbix.Text),
+	}
+
+	defaultPorts = map[zabbix.InterfaceType]string{
+		zabbix.Agent: "10050",
+		zabbix.SNMP:  "161",
+		zabbix.IPMI:  "623",
+		zabbix.JMX:   "12345",
+	}
+)
+
+func (s *Status) UnmarshalYAML(node *yaml.Node) error {
+	v, err := decodeEnum(node, "status", statuses)
+	*s = Status(v)
+	return err
+}
+
+func (t *InterfaceType) UnmarshalYAML(node *yaml.Node) error {
+	v, err := decodeEnum(node, "interface type", interfaceTypes)
+	*t = InterfaceType(v)
+	return err
+}
+
+func (t *ItemType) UnmarshalYAML(node *yaml.Node) error {
+	v, err := decodeEnum(node, "item type", itemTypes)
+	*t = ItemType(v)
+	return err
+}
+
+func (t *ValueType) UnmarshalYAML(node *yaml.Node) error {
+	v, err := decodeEnum(node, "value type", valueTypes)
+	*t = ValueType(v)
+	return err
+}
+
+func decodeEnum(node *yaml.Node, what string, names map[string]int) (int, error) {
+	if node.Kind == yaml.ScalarNode {
+		if v, found := names[strings.ToLower(node.Value)]; found {
+			return v, nil
+		}
+		if v, err := strconv.Atoi(node.Value); err == nil {
+			return v, nil
+		}
+	}
+	return 0, nodeError(node, fmt.Sprintf("unknown %s %q", what, node.Value))
+}
+
+func (f *File) UnmarshalYAML(node *yaml.Node) error {
+	type plain File
+	return decodeStrict(node, (*plain)(f))
+}
+
+func (t *Template) UnmarshalYAML(node *yaml.Node) error {
+	type plain Template
+	t.Line, t.Column = node.Line, node.Column
+	return decodeStrict(node, (*plain)(t))
+}
+
+func (h *Host) UnmarshalYAML(node *yaml.Node) error {
+	type plain Host
+	h.Line, h.Column = node.Line, node.Column
+	return decodeStrict(node, (*plain)(h))
+}
+
+func (i *Interface) UnmarshalYAML(node *yaml.Node) error {
+	type plain Interface
+	return decodeStrict(node, (*plain)(i))
+}
+
+func (i *Item) UnmarshalYAML(node *yaml.Node) error {
+	type plain Item
+	i.Line, i.Column = node.Line, node.Column
+	return decodeStrict(node, (*plain)(i))
+}
+
+// Decodes mapping node into struct pointed by v, reporting fields without yaml tag in v.
+func decodeStrict(node *yaml.Node, v interface{}) error {
+	if node.Kind != yaml.MappingNode {
+		return nodeError(node, "expected mapping")
+	}
+	known := make(map[string]bool)
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !known[key.Value] || key.Value == "-" {
+			return nodeError(key, fmt.Sprintf("unknown field %q", key.Value))
+		}
+	}
+	return node.Decode(v)
+}
+
+func nodeError(node *yaml.Node, message string) *Error {
+	return &Error{Line: node.Line, Column: node.Column, Message: message}
+}
+
+// Reads and parses desired state file.
+func Load(path string) (f *File, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	return Parse(path, data)
+}
+
+// Parses YAML or JSON desired state document; path is used in errors only.
+// Returns Errors if document is valid YAML, but not valid desired state.
+func Parse(path string, data []byte) (f *File, err error) {
+	var root yaml.Node
+	if err = yaml.Unmarshal(data, &root); err != nil {
+		return nil, &Error{File: path, Message: err.Error()}
+	}
+
+	f = &File{Path: path}
+	if len(root.Content) > 0 {
+		if err = root.Content[0].Decode(f); err != nil {
+			if e, ok := err.(*Error); ok {
+				e.File = path
+				return nil, Errors{e}
+			}
+			return nil, &Error{File: path, Message: err.Error()}
+		}
+		f.Path = path
+	}
+
+	if errs := f.validate(); len(errs) > 0 {
+		return nil, errs
+	}
+	return
+}
+
+func (f *File) errorAt(line, column int, format string, args ...interface{}) *Error {
+	return &Error{File: f.Path, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// Checks required fields and duplicates.
+func (f *File) validate() (errs Errors) {
+	unique := func(kind string, names []Name) {
+		seen := make(map[string]bool, len(names))
+		for _, n := range names {
+			if seen[n.Value] {
+				errs = append(errs, f.errorAt(n.Line, n.Column, "duplicate %s %q", kind, n.Value))
+			}
+			seen[n.Value] = true
+		}
+	}
+
+	unique("host group", f.HostGroups)
+	templates := make([]Name, 0, len(f.Templates))
+	for _, t := range f.Templates {
+		if t.Template.Value == "" {
+			errs = append(errs, f.errorAt(t.Line, t.Column, "template without technical name"))
+			continue
+		}
+		templates = append(templates, t.Template)
+	}
+	unique("template", templates)
+
+	hosts := make([]Name, 0, len(f.Hosts))
+	for _, h := range f.Hosts {
+		if h.Host.Value == "" {
+			errs = append(errs, f.errorAt(h.Line, h.Column, "host without technical name"))
+			continue
+		}
+		hosts = append(hosts, h.Host)
+		unique("application", h.Applications)
+
+		keys := make([]Name, 0, len(h.Items))
+		for _, item := range h.Items {
+			if item.Key.Value == "" {
+				errs = append(errs, f.errorAt(item.Line, item.Column, "item without key_"))
+				continue
+			}
+			keys = append(keys, item.Key)
+		}
+		unique("item", keys)
+	}
+	unique("host", hosts)
+	return
+}
+
+// Converts file to desired state.
+func (f *File) desiredState() *zabbix.DesiredState {
+	state := &zabbix.DesiredState{HostGroups: values(f.HostGroups)}
+	for _, t := range f.Templates {
+		state.Templates = append(state.Templates, zabbix.DesiredTemplate{
+			Template: zabbix.Template{Host: t.Template.Value, Name: t.Name, Description: t.Description},
+			Groups:   values(t.Groups),
+		})
+	}
+	for _, h := range f.Hosts {
+		host := zabbix.Host{Host: h.Host.Value, Name: h.Name, Status: zabbix.StatusType(h.Status), Description: h.Description}
+		mains := make(map[zabbix.InterfaceType]bool)
+		for _, iface := range h.Interfaces {
+			hi := zabbix.HostInterface{Type: zabbix.InterfaceType(iface.Type), IP: iface.IP, DNS: iface.DNS, Port: iface.Port}
+			if hi.Port == "" {
+				hi.Port = defaultPorts[hi.Type]
+			}
+			if (iface.Main == nil && !mains[hi.Type]) || (iface.Main != nil && *iface.Main) {
+				hi.Main = 1
+				mains[hi.Type] = true
+			}
+			if (iface.UseIP == nil && iface.IP != "") || (iface.UseIP != nil && *iface.UseIP) {
+				hi.UseIP = 1
+			}
+			host.Interfaces = append(host.Interfaces, hi)
+		}
+
+		dh := zabbix.DesiredHost{Host: host, Groups: values(h.Groups), Templates: values(h.Templates), Applications: values(h.Applications)}
+		for _, item := range h.Items {
+			dh.Items = append(dh.Items, zabbix.DesiredItem{
+				Item: zabbix.Item{
+					Key:         item.Key.Value,
+					Name:        item.Name,
+					Type:        zabbix.ItemType(item.Type),
+					ValueType:   zabbix.ValueType(item.ValueType),
+					Description: item.Description,
+				},
+				Delay:        item.Delay,
+				History:      item.History,
+				Trends:       item.Trends,
+				Applications: values(item.Applications),
+			})
+		}
+		state.Hosts = append(state.Hosts, dh)
+	}
+	return state
+}
+
+func values(names []Name) []string {
+	if names == nil {
+		return nil
+	}
+	res := make([]string, len(names))
+	for i, n := range names {
+		res[i] = n.Value
+	}
+	return res
+}
@@ -0,0 +1,172 @@
+package state_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "."
+	"github.com/AlekSi/zabbix"
+	"github.com/AlekSi/zabbix/internal/zabbixtest"
+)
+
+const testFile = `host_groups:
+  - Linux servers
+hosts:
+  - host: web1
+    name: Web server 1
+    groups: [Linux servers, Web]
+    templates: [Template OS Linux, Template Missing]
+    interfaces:
+      - type: agent
+        ip: 192.0.2.10
+      - type: snmp
+        dns: web1.example.com
+        useip: false
+    applications: [CPU]
+    items:
+      - key_: system.cpu.load
+        name: CPU load
+        type: zabbix_agent
+        value_type: float
+        delay: 60
+        applications: [CPU, Memory, Disk]
+      - key_: lala.trap
+        type: zabbix_trapper
+        value_type: 4
+        history: 7d
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("web.yml", []byte(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Hosts) != 1 || len(f.Hosts[0].Items) != 2 {
+		t.Fatalf("Unexpected file %#v", f)
+	}
+	h := f.Hosts[0]
+	if h.Line != 4 || h.Groups[1] != (Name{"Web", 6, 29}) {
+		t.Errorf("Bad positions: host %d, group %#v", h.Line, h.Groups[1])
+	}
+	item := h.Items[1]
+	if item.Type != ItemType(zabbix.ZabbixTrapper) || item.ValueType != ValueType(zabbix.Text) || item.History != "7d" {
+		t.Errorf("Bad item %#v", item)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	f, err := Parse("web.json", []byte(`{"hosts": [{"host": "web1", "status": "disabled", "groups": ["Linux servers"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Hosts[0].Status != Status(zabbix.Unmonitored) || f.Hosts[0].Groups[0].Value != "Linux servers" {
+		t.Errorf("Unexpected file %#v", f)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for data, expected := range map[string]string{
+		"hosts:\n  - host: web1\n    group: [Linux]\n":                             "bad.yml:3:5: unknown field \"group\"",
+		"hosts:\n  - host: web1\n    items:\n      - key_: a\n        type: foo\n": "bad.yml:5:15: unknown item type \"foo\"",
+		"hosts:\n  - host: web1\n  - host: web1\n":                                 "bad.yml:3:11: duplicate host \"web1\"",
+		"hosts:\n  - name: web1\n":                                                 "bad.yml:2:5: host without technical name",
+		"templates:\n  - name: Web\n":                                              "bad.yml:2:5: template without technical name",
+	} {
+		_, err := Parse("bad.yml", []byte(data))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	server := zabbixtest.Server(t, map[string]interface{}{
+		"hostgroup.get":   []interface{}{},
+		"template.get":    []interface{}{map[string]interface{}{"templateid": "10001", "host": "Template OS Linux"}},
+		"host.get":        []interface{}{map[string]interface{}{"hostid": "10100", "host": "web1"}},
+		"application.get": []interface{}{},
+	})
+	defer server.Close()
+
+	f, err := Parse("web.yml", []byte(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Validate(zabbix.NewAPI(server.URL))
+	expected := `web.yml:6:29: unknown host group "Web"
+web.yml:7:36: unknown template "Template Missing"
+web.yml:21:29: unknown application "Memory" of host "web1"
+web.yml:21:37: unknown application "Disk" of host "web1"`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%v", expected, err)
+	}
+
+	f.Hosts[0].Groups = f.Hosts[0].Groups[:1]
+	f.Hosts[0].Templates = f.Hosts[0].Templates[:1]
+	f.Hosts[0].Items[0].Applications = f.Hosts[0].Items[0].Applications[:1]
+	state, err := f.Validate(zabbix.NewAPI(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := state.Hosts[0].Host
+	expectedInterfaces := zabbix.HostInterfaces{
+		{Type: zabbix.Agent, IP: "192.0.2.10", Port: "10050", Main: 1, UseIP: 1},
+		{Type: zabbix.SNMP, DNS: "web1.example.com", Port: "161", Main: 1},
+	}
+	if !reflect.DeepEqual(host.Interfaces, expectedInterfaces) {
+		t.Errorf("Bad interfaces %#v", host.Interfaces)
+	}
+	if !reflect.DeepEqual(state.Hosts[0].Templates, []string{"Template OS Linux"}) || state.HostGroups[0] != "Linux servers" {
+		t.Errorf("Bad state %#v", state)
+	}
+	item := state.Hosts[0].Items[0]
+	if item.Item.Key != "system.cpu.load" || item.Delay != "60" || !reflect.DeepEqual(item.Applications, []string{"CPU"}) {
+		t.Errorf("Bad item %#v", item)
+	}
+}
+
+const templatesFile = `templates:
+  - template: Template Web
+    name: Web server
+    groups: [Templates, Missing]
+hosts:
+  - host: web1
+    groups: [Linux servers]
+    templates: [Template Web, Template OS Linux, Template Missing]
+`
+
+func TestValidateTemplates(t *testing.T) {
+	server := zabbixtest.Server(t, map[string]interface{}{
+		"hostgroup.get": []interface{}{
+			map[string]interface{}{"groupid": "1", "name": "Templates"},
+			map[string]interface{}{"groupid": "2", "name": "Linux servers"},
+		},
+		"template.get": []interface{}{map[string]interface{}{"templateid": "10001", "host": "Template OS Linux"}},
+	})
+	defer server.Close()
+
+	f, err := Parse("templates.yml", []byte(templatesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Validate(zabbix.NewAPI(server.URL))
+	expected := `templates.yml:4:25: unknown host group "Missing"
+templates.yml:8:50: unknown template "Template Missing"`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%v", expected, err)
+	}
+
+	f.Templates[0].Groups = f.Templates[0].Groups[:1]
+	f.Hosts[0].Templates = f.Hosts[0].Templates[:2]
+	state, err := f.Validate(zabbix.NewAPI(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTemplates := []zabbix.DesiredTemplate{{Template: zabbix.Template{Host: "Template Web", Name: "Web server"}, Groups: []string{"Templates"}}}
+	if !reflect.DeepEqual(state.Templates, expectedTemplates) {
+		t.Errorf("Bad templates %#v", state.Templates)
+	}
+	if !reflect.DeepEqual(state.Hosts[0].Templates, []string{"Template Web", "Template OS Linux"}) || state.Hosts[0].Host.Templates != nil {
+		t.Errorf("Bad host %#v", state.Hosts[0])
+	}
+}
@@ -0,0 +1,159 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/AlekSi/zabbix"
+)
+
+// Checks host groups, templates and applications referenced in file are declared in it or exist on server,
+// and returns desired state for zabbix.API.SyncPlan. Returns Errors with positions of all unresolved references.
+// Names are not converted to ids: SyncPlan looks up objects itself, as it compares them with desired state,
+// and reports objects removed after validation as *zabbix.SyncUnresolved.
+func (f *File) Validate(api *zabbix.API) (state *zabbix.DesiredState, err error) {
+	var errs Errors
+
+	if err = f.checkHostGroups(api, &errs); err != nil {
+		return
+	}
+
+	if err = f.checkTemplates(api, &errs); err != nil {
+		return
+	}
+
+	if err = f.checkApplications(api, &errs); err != nil {
+		return
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+		return nil, errs
+	}
+	return f.desiredState(), nil
+}
+
+// Checks host groups referenced by templates and hosts are declared in file or exist.
+func (f *File) checkHostGroups(api *zabbix.API, errs *Errors) (err error) {
+	declared := make(map[string]bool, len(f.HostGroups))
+	for _, g := range f.HostGroups {
+		declared[g.Value] = true
+	}
+	var refs []Name
+	for _, t := range f.Templates {
+		refs = append(refs, t.Groups...)
+	}
+	for _, h := range f.Hosts {
+		refs = append(refs, h.Groups...)
+	}
+	var names []string
+	for _, g := range refs {
+		if !declared[g.Value] {
+			names = append(names, g.Value)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	groups, err := api.HostGroupsGet(zabbix.Params{"filter": map[string]interface{}{"name": names}})
+	if err != nil {
+		return
+	}
+	existing := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		existing[g.Name] = true
+	}
+
+	for _, g := range refs {
+		if !existing[g.Value] && !declared[g.Value] {
+			*errs = append(*errs, f.errorAt(g.Line, g.Column, "unknown host group %q", g.Value))
+		}
+	}
+	return
+}
+
+// Checks templates referenced by hosts are declared in file or exist.
+func (f *File) checkTemplates(api *zabbix.API, errs *Errors) (err error) {
+	declared := make(map[string]bool, len(f.Templates))
+	for _, t := range f.Templates {
+		declared[t.Template.Value] = true
+	}
+	var names []string
+	for _, h := range f.Hosts {
+		for _, t := range h.Templates {
+			if !declared[t.Value] {
+				names = append(names, t.Value)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	templates, err := api.TemplatesGet(zabbix.Params{
+		"filter": map[string]interface{}{"host": names},
+		"output": []string{"templateid", "host"},
+	})
+	if err != nil {
+		return
+	}
+	existing := make(map[string]bool, len(templates))
+	for _, t := range templates {
+		existing[t.Host] = true
+	}
+
+	for _, h := range f.Hosts {
+		for _, t := range h.Templates {
+			if !existing[t.Value] && !declared[t.Value] {
+				*errs = append(*errs, f.errorAt(t.Line, t.Column, "unknown template %q", t.Value))
+			}
+		}
+	}
+	return
+}
+
+// Checks applications referenced by items are declared for the same host or exist on it.
+func (f *File) checkApplications(api *zabbix.API, errs *Errors) (err error) {
+	for _, h := range f.Hosts {
+		declared := make(map[string]bool, len(h.Applications))
+		for _, a := range h.Applications {
+			declared[a.Value] = true
+		}
+
+		var host *zabbix.Host
+		hostChecked := false
+		existing := make(map[string]bool)
+		for _, item := range h.Items {
+			for _, a := range item.Applications {
+				if declared[a.Value] || existing[a.Value] {
+					continue
+				}
+
+				if !hostChecked {
+					hostChecked = true
+					host, err = api.HostGetByHost(h.Host.Value)
+					if _, notFound := err.(*zabbix.ExpectedOneResult); notFound {
+						err = nil
+					}
+					if err != nil {
+						return
+					}
+				}
+				if host != nil {
+					_, err = api.ApplicationGetByHostIdAndName(host.HostId, a.Value)
+					if _, notFound := err.(*zabbix.ExpectedOneResult); notFound {
+						err = nil
+					} else if err == nil {
+						existing[a.Value] = true
+						continue
+					}
+					if err != nil {
+						return
+					}
+				}
+				*errs = append(*errs, f.errorAt(a.Line, a.Column, "unknown application %q of host %q", a.Value, h.Host.Value))
+			}
+		}
+	}
+	return
+}
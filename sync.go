@@ -0,0 +1,921 @@
+package zabbix
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlekSi/zabbix/internal/apiutil"
+)
+
+type (
+	SyncAction int
+	SyncKind   int
+)
+
+const (
+	SyncCreate SyncAction = 0
+	SyncUpdate SyncAction = 1
+	SyncDelete SyncAction = 2
+
+	SyncHostGroup   SyncKind = 0
+	SyncHost        SyncKind = 1
+	SyncApplication SyncKind = 2
+	SyncItem        SyncKind = 3
+	SyncTemplate    SyncKind = 4
+)
+
+func (a SyncAction) String() string {
+	return [...]string{"create", "update", "delete"}[a]
+}
+
+func (k SyncKind) String() string {
+	return [...]string{"host group", "host", "application", "item", "template"}[k]
+}
+
+// Desired configuration of host groups, templates and hosts with their applications and items.
+// Objects are matched with existing ones by natural keys: host group name, template and host technical names,
+// application name and item key within host.
+type DesiredState struct {
+	HostGroups []string // managed host groups, created if missing
+	Templates  []DesiredTemplate
+	Hosts      []DesiredHost
+}
+
+type DesiredTemplate struct {
+	Template Template // TemplateId and GroupIds are ignored; name and description are updated only if not empty
+	Groups   []string // names of declared or existing host groups
+}
+
+type DesiredHost struct {
+	Host         Host     // HostId and GroupIds are ignored
+	Groups       []string // names of declared or existing host groups
+	Templates    []string // technical names of declared or existing templates; replace Host.Templates if not nil
+	Applications []string
+	Items        []DesiredItem
+}
+
+type DesiredItem struct {
+	Item         Item     // ItemId, HostId, ApplicationIds, Delay, History and Trends are ignored; InterfaceId is set to main host interface if empty
+	Delay        string   // update interval like 60 or 1m
+	History      string   // like 7 or 7d, not changed if empty; number is days before Zabbix 3.4, seconds since
+	Trends       string   // as History
+	Applications []string // names of declared or existing applications of the same host
+}
+
+type SyncDuplicate struct {
+	Kind SyncKind
+	Name string
+}
+
+func (e *SyncDuplicate) Error() string {
+	return fmt.Sprintf("Duplicate %s %q in desired state.", e.Kind, e.Name)
+}
+
+type SyncUnresolved struct {
+	Kind SyncKind
+	Name string
+	From string // natural key of object with reference
+}
+
+func (e *SyncUnresolved) Error() string {
+	return fmt.Sprintf("Unresolved %s %q referenced by %q.", e.Kind, e.Name, e.From)
+}
+
+// Single change of sync plan.
+type SyncChange struct {
+	Action SyncAction
+	Kind   SyncKind
+	Name   string   // natural key: group, host, host/application or host/key
+	Fields []string // JSON names of changed fields, for updates only
+
+	host      string      // name of host for applications and items
+	object    interface{} // *HostGroup, *Template, *Host, *Application or *Item (*syncItem for creates and updates) with known ids
+	refs      []string    // names of host groups for templates and hosts, names of applications for items
+	templates []string    // names of templates for hosts, nil if Host.Templates is used as is
+}
+
+// Changes needed to reach desired state, in the order they are applied:
+// host groups, templates, hosts, applications and items are created and updated first, then
+// items, applications and hosts are deleted.
+type SyncPlan struct {
+	Changes []SyncChange
+
+	groupIds    map[string]string         // by name
+	templateIds map[string]string         // by host
+	hostIds     map[string]string         // by host
+	appIds      map[string]string         // by host/application
+	interfaces  map[string]HostInterfaces // by host
+}
+
+// Returns plan in human-readable form, one change per line: "+" for create, "~" for update, "-" for delete.
+func (p *SyncPlan) String() string {
+	var b bytes.Buffer
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "%c %s %s", "+~-"[c.Action], c.Kind, c.Name)
+		if len(c.Fields) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(c.Fields, ", "))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (p *SyncPlan) add(c SyncChange) {
+	p.Changes = append(p.Changes, c)
+}
+
+// Sorts changes in dependency order keeping order of changes of the same phase.
+func (p *SyncPlan) sort() {
+	order := [...]int{SyncHostGroup: 0, SyncTemplate: 1, SyncHost: 2, SyncApplication: 3, SyncItem: 4}
+	phase := func(c SyncChange) int {
+		if c.Action == SyncDelete {
+			return 10 - order[c.Kind]
+		}
+		return order[c.Kind]
+	}
+	sort.SliceStable(p.Changes, func(i, j int) bool {
+		return phase(p.Changes[i]) < phase(p.Changes[j])
+	})
+}
+
+func (s *DesiredState) validate() error {
+	check := func(kind SyncKind, prefix string, names []string) error {
+		seen := make(map[string]bool, len(names))
+		for _, n := range names {
+			if seen[n] {
+				return &SyncDuplicate{kind, prefix + n}
+			}
+			seen[n] = true
+		}
+		return nil
+	}
+
+	if err := check(SyncHostGroup, "", s.HostGroups); err != nil {
+		return err
+	}
+	templates := make([]string, len(s.Templates))
+	for i, t := range s.Templates {
+		templates[i] = t.Template.Host
+	}
+	if err := check(SyncTemplate, "", templates); err != nil {
+		return err
+	}
+	hosts := make([]string, len(s.Hosts))
+	for i, h := range s.Hosts {
+		hosts[i] = h.Host.Host
+		if err := check(SyncApplication, h.Host.Host+"/", h.Applications); err != nil {
+			return err
+		}
+		keys := make([]string, len(h.Items))
+		for j, item := range h.Items {
+			keys[j] = item.Item.Key
+		}
+		if err := check(SyncItem, h.Host.Host+"/", keys); err != nil {
+			return err
+		}
+	}
+	return check(SyncHost, "", hosts)
+}
+
+// Computes changes needed to reach desired state. If prune is true, plan also deletes
+// hosts of managed host groups, applications and items of desired hosts which are not in desired state.
+// Host groups and templates are never deleted; objects inherited from templates or discovered are never changed.
+func (api *API) SyncPlan(state *DesiredState, prune bool) (plan *SyncPlan, err error) {
+	if err = state.validate(); err != nil {
+		return
+	}
+	plan = &SyncPlan{
+		groupIds:    make(map[string]string),
+		templateIds: make(map[string]string),
+		hostIds:     make(map[string]string),
+		appIds:      make(map[string]string),
+		interfaces:  make(map[string]HostInterfaces),
+	}
+
+	if err = api.planHostGroups(plan, state); err != nil {
+		return
+	}
+	if err = api.planTemplates(plan, state); err != nil {
+		return
+	}
+	if err = api.planHosts(plan, state, prune); err != nil {
+		return
+	}
+	if err = api.planHostContents(plan, state, prune); err != nil {
+		return
+	}
+	plan.sort()
+	return
+}
+
+func (api *API) planHostGroups(plan *SyncPlan, state *DesiredState) (err error) {
+	declared := make(map[string]bool)
+	names := append([]string{}, state.HostGroups...)
+	for _, name := range state.HostGroups {
+		declared[name] = true
+	}
+	for _, t := range state.Templates {
+		names = append(names, t.Groups...)
+	}
+	for _, h := range state.Hosts {
+		names = append(names, h.Groups...)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	groups, err := api.HostGroupsGet(Params{"filter": map[string]interface{}{"name": names}})
+	if err != nil {
+		return
+	}
+	for _, g := range groups {
+		plan.groupIds[g.Name] = g.GroupId
+	}
+
+	for _, name := range state.HostGroups {
+		if _, exists := plan.groupIds[name]; !exists {
+			plan.add(SyncChange{Action: SyncCreate, Kind: SyncHostGroup, Name: name, object: &HostGroup{Name: name}})
+		}
+	}
+	for _, t := range state.Templates {
+		for _, name := range t.Groups {
+			if _, exists := plan.groupIds[name]; !exists && !declared[name] {
+				return &SyncUnresolved{SyncHostGroup, name, t.Template.Host}
+			}
+		}
+	}
+	for _, h := range state.Hosts {
+		for _, name := range h.Groups {
+			if _, exists := plan.groupIds[name]; !exists && !declared[name] {
+				return &SyncUnresolved{SyncHostGroup, name, h.Host.Host}
+			}
+		}
+	}
+	return
+}
+
+func (api *API) planTemplates(plan *SyncPlan, state *DesiredState) (err error) {
+	declared := make(map[string]bool)
+	var names []string
+	for _, t := range state.Templates {
+		declared[t.Template.Host] = true
+		names = append(names, t.Template.Host)
+	}
+	for _, h := range state.Hosts {
+		names = append(names, h.Templates...)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	templates, err := api.TemplatesGet(Params{"filter": map[string]interface{}{"host": names}}, SelectGroups)
+	if err != nil {
+		return
+	}
+	existing := make(map[string]*Template, len(templates))
+	for i := range templates {
+		existing[templates[i].Host] = &templates[i]
+		plan.templateIds[templates[i].Host] = templates[i].TemplateId
+	}
+
+	for _, dt := range state.Templates {
+		template := dt.Template
+		template.TemplateId, template.GroupIds = "", nil
+		cur := existing[template.Host]
+		if cur == nil {
+			plan.add(SyncChange{Action: SyncCreate, Kind: SyncTemplate, Name: template.Host, object: &template, refs: dt.Groups})
+			continue
+		}
+
+		template.TemplateId = cur.TemplateId
+		if fields := plan.diffTemplate(cur, &template, dt.Groups); len(fields) > 0 {
+			plan.add(SyncChange{Action: SyncUpdate, Kind: SyncTemplate, Name: template.Host, Fields: fields, object: &template, refs: dt.Groups})
+		}
+	}
+	for _, h := range state.Hosts {
+		for _, name := range h.Templates {
+			if _, exists := plan.templateIds[name]; !exists && !declared[name] {
+				return &SyncUnresolved{SyncTemplate, name, h.Host.Host}
+			}
+		}
+	}
+	return
+}
+
+// Returns JSON names of fields of existing template which differ from desired one.
+func (plan *SyncPlan) diffTemplate(cur, template *Template, groups []string) (fields []string) {
+	if template.Name != "" && template.Name != cur.Name {
+		fields = append(fields, "name")
+	}
+	if template.Description != "" && template.Description != cur.Description {
+		fields = append(fields, "description")
+	}
+	curGroups := make([]string, len(cur.GroupIds))
+	for i, g := range cur.GroupIds {
+		curGroups[i] = g.GroupId
+	}
+	groupIds := make([]string, len(groups))
+	for i, name := range groups {
+		groupIds[i] = plan.groupIds[name]
+	}
+	if !sameSet(curGroups, groupIds) {
+		fields = append(fields, "groups")
+	}
+	return
+}
+
+func (api *API) planHosts(plan *SyncPlan, state *DesiredState, prune bool) (err error) {
+	names := make([]string, len(state.Hosts))
+	desired := make(map[string]bool, len(state.Hosts))
+	for i, h := range state.Hosts {
+		names[i] = h.Host.Host
+		desired[h.Host.Host] = true
+	}
+
+	var hosts Hosts
+	if len(names) > 0 {
+		hosts, err = api.HostsGet(Params{"filter": map[string]interface{}{"host": names}}, SelectGroups, SelectInterfaces, SelectParentTemplates)
+		if err != nil {
+			return
+		}
+	}
+	existing := make(map[string]*Host, len(hosts))
+	for i := range hosts {
+		existing[hosts[i].Host] = &hosts[i]
+	}
+
+	for _, dh := range state.Hosts {
+		host := dh.Host
+		host.HostId, host.GroupIds = "", nil
+		cur := existing[host.Host]
+		if cur == nil {
+			plan.add(SyncChange{Action: SyncCreate, Kind: SyncHost, Name: host.Host, object: &host, refs: dh.Groups, templates: dh.Templates})
+			continue
+		}
+
+		plan.hostIds[host.Host] = cur.HostId
+		plan.interfaces[host.Host] = cur.Interfaces
+		host.HostId = cur.HostId
+		if fields := plan.diffHost(cur, &host, dh.Groups, dh.Templates); len(fields) > 0 {
+			plan.add(SyncChange{Action: SyncUpdate, Kind: SyncHost, Name: host.Host, Fields: fields, object: &host, refs: dh.Groups, templates: dh.Templates})
+		}
+	}
+
+	if !prune {
+		return
+	}
+	var groupIds []string
+	for _, name := range state.HostGroups {
+		if id := plan.groupIds[name]; id != "" {
+			groupIds = append(groupIds, id)
+		}
+	}
+	if len(groupIds) == 0 {
+		return
+	}
+	managed, err := api.HostsGet(Params{"groupids": groupIds, "output": []string{"hostid", "host"}, "sortfield": "host"})
+	if err != nil {
+		return
+	}
+	for i := range managed {
+		if !desired[managed[i].Host] {
+			plan.add(SyncChange{Action: SyncDelete, Kind: SyncHost, Name: managed[i].Host, object: &managed[i]})
+		}
+	}
+	return
+}
+
+// Returns JSON names of fields of existing host which differ from desired one.
+// Templates are compared by names if they are not nil, by ids of Host.Templates otherwise.
+// Interfaces of desired host get ids of matching existing ones.
+func (plan *SyncPlan) diffHost(cur, host *Host, groups, templates []string) (fields []string) {
+	if host.Name != "" && host.Name != cur.Name {
+		fields = append(fields, "name")
+	}
+	if host.Status != cur.Status {
+		fields = append(fields, "status")
+	}
+	if host.Description != cur.Description {
+		fields = append(fields, "description")
+	}
+	if zeroId(host.ProxyHostId) != zeroId(cur.ProxyHostId) {
+		fields = append(fields, "proxy_hostid")
+	}
+
+	curGroups := make([]string, len(cur.GroupIds))
+	for i, g := range cur.GroupIds {
+		curGroups[i] = g.GroupId
+	}
+	groupIds := make([]string, len(groups))
+	for i, name := range groups {
+		groupIds[i] = plan.groupIds[name]
+	}
+	if !sameSet(curGroups, groupIds) {
+		fields = append(fields, "groups")
+	}
+
+	if templates != nil {
+		a, b := make([]string, len(templates)), make([]string, len(cur.Templates))
+		for i, name := range templates {
+			a[i] = plan.templateIds[name]
+		}
+		for i, t := range cur.Templates {
+			b[i] = t.TemplateId
+		}
+		if !sameSet(a, b) {
+			fields = append(fields, "templates")
+		}
+	} else if host.Templates != nil {
+		a, b := make([]string, len(host.Templates)), make([]string, len(cur.Templates))
+		for i, t := range host.Templates {
+			a[i] = t.TemplateId
+		}
+		for i, t := range cur.Templates {
+			b[i] = t.TemplateId
+		}
+		if !sameSet(a, b) {
+			fields = append(fields, "templates")
+		}
+	}
+
+	if host.Interfaces != nil {
+		key := func(i HostInterface) string {
+			return fmt.Sprintf("%d/%d/%d/%s/%s/%s", i.Type, i.Main, i.UseIP, i.IP, i.DNS, i.Port)
+		}
+		a, b := make([]string, len(host.Interfaces)), make([]string, len(cur.Interfaces))
+		for i, iface := range host.Interfaces {
+			a[i] = key(iface)
+		}
+		for i, iface := range cur.Interfaces {
+			b[i] = key(iface)
+		}
+		if !sameSet(a, b) {
+			fields = append(fields, "interfaces")
+			host.Interfaces = append(HostInterfaces{}, host.Interfaces...)
+			for i, iface := range host.Interfaces {
+				for _, c := range cur.Interfaces {
+					if c.Type == iface.Type && c.Main == iface.Main {
+						host.Interfaces[i].InterfaceId = c.InterfaceId
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func (api *API) planHostContents(plan *SyncPlan, state *DesiredState, prune bool) (err error) {
+	var hostIds []string
+	for _, id := range plan.hostIds {
+		hostIds = append(hostIds, id)
+	}
+
+	// applications with ids of their items, by host id and name
+	apps := make(map[string]map[string]*syncApplication)
+	items := make(map[string]map[string]*syncItem)
+	storage := 24 * time.Hour // unit of history and trends without suffix
+	if len(hostIds) > 0 {
+		var version string
+		if version, err = api.Version(); err != nil {
+			return
+		}
+		if !apiutil.OlderThan(version, 3, 4) {
+			storage = time.Second
+		}
+		if apps, err = api.syncApplications(hostIds); err != nil {
+			return
+		}
+		if items, err = api.syncItems(hostIds); err != nil {
+			return
+		}
+	}
+
+	for _, dh := range state.Hosts {
+		host := dh.Host.Host
+		hostId := plan.hostIds[host]
+		curApps, curItems := apps[hostId], items[hostId]
+
+		declared := make(map[string]bool, len(dh.Applications))
+		for _, name := range dh.Applications {
+			declared[name] = true
+			if app := curApps[name]; app != nil {
+				plan.appIds[host+"/"+name] = app.ApplicationId
+				continue
+			}
+			plan.add(SyncChange{Action: SyncCreate, Kind: SyncApplication, Name: host + "/" + name, host: host, object: &Application{Name: name}})
+		}
+		for name, app := range curApps {
+			if !declared[name] {
+				plan.appIds[host+"/"+name] = app.ApplicationId
+			}
+		}
+
+		desiredKeys := make(map[string]bool, len(dh.Items))
+		for _, di := range dh.Items {
+			item := syncItem{Item: di.Item, Delay: di.Delay, History: di.History, Trends: di.Trends}
+			name := host + "/" + item.Key
+			desiredKeys[item.Key] = true
+			for _, app := range di.Applications {
+				if _, exists := curApps[app]; !exists && !declared[app] {
+					return &SyncUnresolved{SyncApplication, app, name}
+				}
+			}
+
+			item.ItemId, item.HostId, item.ApplicationIds = "", hostId, nil
+			cur := curItems[item.Key]
+			if cur == nil {
+				plan.add(SyncChange{Action: SyncCreate, Kind: SyncItem, Name: name, host: host, object: &item, refs: di.Applications})
+				continue
+			}
+			if zeroId(cur.TemplateId) != "" || cur.Flags == 4 {
+				continue
+			}
+			item.ItemId = cur.ItemId
+			var curItemApps []string
+			for appName, app := range curApps {
+				if app.itemIds[cur.ItemId] {
+					curItemApps = append(curItemApps, appName)
+				}
+			}
+			if fields := diffItem(cur, &item, curItemApps, di.Applications, storage); len(fields) > 0 {
+				plan.add(SyncChange{Action: SyncUpdate, Kind: SyncItem, Name: name, Fields: fields, host: host, object: &item, refs: di.Applications})
+			}
+		}
+
+		if !prune || hostId == "" {
+			continue
+		}
+		keys := make([]string, 0, len(curItems))
+		for key := range curItems {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			cur := curItems[key]
+			if !desiredKeys[key] && zeroId(cur.TemplateId) == "" && cur.Flags != 4 {
+				plan.add(SyncChange{Action: SyncDelete, Kind: SyncItem, Name: host + "/" + key, host: host, object: &cur.Item})
+			}
+		}
+		names := make([]string, 0, len(curApps))
+		for name := range curApps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if app := curApps[name]; !declared[name] && !app.inherited {
+				plan.add(SyncChange{Action: SyncDelete, Kind: SyncApplication, Name: host + "/" + name, host: host, object: &app.Application})
+			}
+		}
+	}
+	return
+}
+
+// Item with time values as strings, which have suffixes like "30s" or "90d" since Zabbix 3.4.
+type syncItem struct {
+	Item
+	Delay   string `json:"delay"`
+	History string `json:"history,omitempty"`
+	Trends  string `json:"trends,omitempty"`
+}
+
+// Gets items of hosts by host id and key.
+func (api *API) syncItems(hostIds []string) (res map[string]map[string]*syncItem, err error) {
+	response, err := api.CallWithError("item.get", Params{"hostids": hostIds, "output": "extend", "sortfield": "key_"})
+	if err != nil {
+		return
+	}
+
+	res = make(map[string]map[string]*syncItem)
+	for _, m := range apiutil.Objects(response.Result) {
+		item := &syncItem{Delay: apiutil.Str(m["delay"]), History: apiutil.Str(m["history"]), Trends: apiutil.Str(m["trends"])}
+		delete(m, "delay")
+		delete(m, "history")
+		delete(m, "trends")
+		decodeResult(m, &item.Item)
+		if res[item.HostId] == nil {
+			res[item.HostId] = make(map[string]*syncItem)
+		}
+		res[item.HostId][item.Key] = item
+	}
+	return
+}
+
+type syncApplication struct {
+	Application
+	inherited bool
+	itemIds   map[string]bool
+}
+
+// Gets applications of hosts with ids of their items.
+func (api *API) syncApplications(hostIds []string) (res map[string]map[string]*syncApplication, err error) {
+	response, err := api.CallWithError("application.get", Params{
+		"hostids":     hostIds,
+		"output":      "extend",
+		"selectItems": []string{"itemid"},
+	})
+	if err != nil {
+		return
+	}
+
+	res = make(map[string]map[string]*syncApplication)
+	for _, r := range response.Result.([]interface{}) {
+		m := r.(map[string]interface{})
+		app := &syncApplication{itemIds: make(map[string]bool)}
+		decodeResult(m, &app.Application)
+
+		// templateid in Zabbix 2.0, templateids since 2.4; flags since 3.0
+		templateIds, _ := m["templateids"].([]interface{})
+		flags, _ := m["flags"].(string)
+		app.inherited = zeroId(app.TemplateId) != "" || len(templateIds) > 0 || flags == "4"
+
+		items, _ := m["items"].([]interface{})
+		for _, i := range items {
+			app.itemIds[i.(map[string]interface{})["itemid"].(string)] = true
+		}
+		if res[app.HostId] == nil {
+			res[app.HostId] = make(map[string]*syncApplication)
+		}
+		res[app.HostId][app.Name] = app
+	}
+	return
+}
+
+// Returns JSON names of fields of existing item which differ from desired one.
+// Time values are compared as durations; history and trends without suffix are multiplied by storage.
+func diffItem(cur, item *syncItem, curApps, apps []string, storage time.Duration) (fields []string) {
+	if item.Name != cur.Name {
+		fields = append(fields, "name")
+	}
+	if item.Type != cur.Type {
+		fields = append(fields, "type")
+	}
+	if item.ValueType != cur.ValueType {
+		fields = append(fields, "value_type")
+	}
+	if !sameDuration(item.Delay, cur.Delay, time.Second) {
+		fields = append(fields, "delay")
+	}
+	if item.DataType != cur.DataType {
+		fields = append(fields, "data_type")
+	}
+	if item.Delta != cur.Delta {
+		fields = append(fields, "delta")
+	}
+	if item.Description != cur.Description {
+		fields = append(fields, "description")
+	}
+	if item.History != "" && !sameDuration(item.History, cur.History, storage) {
+		fields = append(fields, "history")
+	}
+	if item.Trends != "" && !sameDuration(item.Trends, cur.Trends, storage) {
+		fields = append(fields, "trends")
+	}
+	if item.InterfaceId != "" && item.InterfaceId != cur.InterfaceId {
+		fields = append(fields, "interfaceid")
+	}
+	if !sameSet(curApps, apps) {
+		fields = append(fields, "applications")
+	}
+	return
+}
+
+// Checks if time values like 60, 1m or 3600s are equal. Flexible intervals and user macros are compared as strings.
+func sameDuration(a, b string, plain time.Duration) bool {
+	if a == b {
+		return true
+	}
+	if strings.Contains(a, ";") || strings.Contains(b, ";") {
+		return false
+	}
+	da, okA := apiutil.ParseDuration(a, plain)
+	db, okB := apiutil.ParseDuration(b, plain)
+	return okA && okB && da == db
+}
+
+// Applies changes of plan in order, batching consecutive changes of the same kind and action.
+// Stops at first error; changes applied before it are not rolled back.
+func (api *API) SyncApply(plan *SyncPlan) (err error) {
+	changes := plan.Changes
+	for start := 0; start < len(changes); {
+		end := start + 1
+		for end < len(changes) && changes[end].Kind == changes[start].Kind && changes[end].Action == changes[start].Action {
+			end++
+		}
+		if err = api.syncBatch(plan, changes[start:end]); err != nil {
+			return
+		}
+		start = end
+	}
+	return
+}
+
+func (api *API) syncBatch(plan *SyncPlan, changes []SyncChange) (err error) {
+	c := changes[0]
+	switch {
+	case c.Kind == SyncHostGroup && c.Action == SyncCreate:
+		groups := make(HostGroups, len(changes))
+		for i, c := range changes {
+			groups[i] = *c.object.(*HostGroup)
+		}
+		if err = api.HostGroupsCreate(groups); err != nil {
+			return
+		}
+		for _, g := range groups {
+			plan.groupIds[g.Name] = g.GroupId
+		}
+
+	case c.Kind == SyncTemplate && c.Action == SyncCreate:
+		templates := make(Templates, len(changes))
+		for i, c := range changes {
+			templates[i] = *c.object.(*Template)
+			templates[i].GroupIds = plan.resolveGroups(c.refs)
+		}
+		if err = api.TemplatesCreate(templates); err != nil {
+			return
+		}
+		for _, t := range templates {
+			plan.templateIds[t.Host] = t.TemplateId
+		}
+
+	case c.Kind == SyncTemplate && c.Action == SyncUpdate:
+		for _, c := range changes {
+			template := *c.object.(*Template)
+			template.GroupIds = plan.resolveGroups(c.refs)
+			if err = api.TemplatesUpdate(Templates{template}, c.Fields...); err != nil {
+				return
+			}
+		}
+
+	case c.Kind == SyncHost && c.Action == SyncCreate:
+		hosts := make(Hosts, len(changes))
+		for i, c := range changes {
+			hosts[i] = *c.object.(*Host)
+			hosts[i].GroupIds = plan.resolveGroups(c.refs)
+			if c.templates != nil {
+				hosts[i].Templates = plan.resolveTemplates(c.templates)
+			}
+		}
+		if err = api.HostsCreate(hosts); err != nil {
+			return
+		}
+		for _, h := range hosts {
+			plan.hostIds[h.Host] = h.HostId
+		}
+
+	case c.Kind == SyncHost && c.Action == SyncUpdate:
+		for _, c := range changes {
+			host := *c.object.(*Host)
+			host.GroupIds = plan.resolveGroups(c.refs)
+			if c.templates != nil {
+				host.Templates = plan.resolveTemplates(c.templates)
+			}
+			if err = api.HostsUpdate(Hosts{host}, c.Fields...); err != nil {
+				return
+			}
+		}
+
+	case c.Kind == SyncApplication && c.Action == SyncCreate:
+		apps := make(Applications, len(changes))
+		for i, c := range changes {
+			apps[i] = *c.object.(*Application)
+			apps[i].HostId = plan.hostIds[c.host]
+		}
+		if err = api.ApplicationsCreate(apps); err != nil {
+			return
+		}
+		for i, c := range changes {
+			plan.appIds[c.host+"/"+apps[i].Name] = apps[i].ApplicationId
+		}
+
+	case c.Kind == SyncItem && c.Action == SyncCreate:
+		items := make([]syncItem, len(changes))
+		for i, c := range changes {
+			if items[i], err = api.syncItem(plan, c); err != nil {
+				return
+			}
+		}
+		_, err = api.CallWithError("item.create", items)
+
+	case c.Kind == SyncItem && c.Action == SyncUpdate:
+		for _, c := range changes {
+			var item syncItem
+			if item, err = api.syncItem(plan, c); err != nil {
+				return
+			}
+			var params Params
+			if params, err = updateParams(item.Item, "itemid", nil, c.Fields); err != nil {
+				return
+			}
+			for f, v := range map[string]string{"delay": item.Delay, "history": item.History, "trends": item.Trends} {
+				if _, changed := params[f]; changed {
+					params[f] = v
+				}
+			}
+			if _, err = api.CallWithError("item.update", []Params{params}); err != nil {
+				return
+			}
+		}
+
+	case c.Action == SyncDelete:
+		ids := make([]string, len(changes))
+		for i, c := range changes {
+			switch o := c.object.(type) {
+			case *Host:
+				ids[i] = o.HostId
+			case *Application:
+				ids[i] = o.ApplicationId
+			case *Item:
+				ids[i] = o.ItemId
+			}
+		}
+		switch c.Kind {
+		case SyncHost:
+			err = api.HostsDeleteByIds(ids)
+		case SyncApplication:
+			err = api.ApplicationsDeleteByIds(ids)
+		case SyncItem:
+			err = api.ItemsDeleteByIds(ids)
+		}
+	}
+	return
+}
+
+func (plan *SyncPlan) resolveGroups(names []string) (ids HostGroupIds) {
+	ids = make(HostGroupIds, len(names))
+	for i, name := range names {
+		ids[i] = HostGroupId{plan.groupIds[name]}
+	}
+	return
+}
+
+func (plan *SyncPlan) resolveTemplates(names []string) (ids TemplateIds) {
+	ids = make(TemplateIds, len(names))
+	for i, name := range names {
+		ids[i] = TemplateId{plan.templateIds[name]}
+	}
+	return
+}
+
+// Returns item of change with ids of host, applications and interface filled.
+func (api *API) syncItem(plan *SyncPlan, c SyncChange) (item syncItem, err error) {
+	item = *c.object.(*syncItem)
+	item.HostId = plan.hostIds[c.host]
+	item.ApplicationIds = make([]string, len(c.refs))
+	for i, name := range c.refs {
+		item.ApplicationIds[i] = plan.appIds[c.host+"/"+name]
+	}
+
+	ifaceType, needed := itemInterfaceTypes[item.Type]
+	if item.InterfaceId != "" || !needed {
+		return
+	}
+	ifaces, known := plan.interfaces[c.host]
+	if !known {
+		if ifaces, err = api.HostInterfacesGet(Params{"hostids": item.HostId}); err != nil {
+			return
+		}
+		plan.interfaces[c.host] = ifaces
+	}
+	for _, iface := range ifaces {
+		if iface.Type == ifaceType && iface.Main == 1 {
+			item.InterfaceId = iface.InterfaceId
+		}
+	}
+	return
+}
+
+// Types of host interfaces used by item types.
+var itemInterfaceTypes = map[ItemType]InterfaceType{
+	ZabbixAgent: Agent,
+	SNMPv1Agent: SNMP,
+	SNMPv2Agent: SNMP,
+	SNMPv3Agent: SNMP,
+	IPMIAgent:   IPMI,
+	JMXAgent:    JMX,
+}
+
+// Returns empty string for ids which are "0" in API results.
+func zeroId(id string) string {
+	if id == "0" {
+		return ""
+	}
+	return id
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa, sb := append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
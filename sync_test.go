@@ -0,0 +1,223 @@
+package zabbix_test
+
+import (
+	. "."
+	"reflect"
+	"testing"
+
+	"github.com/AlekSi/zabbix/internal/zabbixtest"
+)
+
+func TestSyncPlanString(t *testing.T) {
+	plan := &SyncPlan{Changes: []SyncChange{
+		{Action: SyncCreate, Kind: SyncHostGroup, Name: "Linux servers"},
+		{Action: SyncCreate, Kind: SyncTemplate, Name: "Template Web"},
+		{Action: SyncUpdate, Kind: SyncHost, Name: "web1", Fields: []string{"name", "groups"}},
+		{Action: SyncDelete, Kind: SyncItem, Name: "web1/agent.ping"},
+	}}
+	expected := "+ host group Linux servers\n+ template Template Web\n~ host web1 (name, groups)\n- item web1/agent.ping\n"
+	if s := plan.String(); s != expected {
+		t.Errorf("Bad plan:\n%s\nexpected:\n%s", s, expected)
+	}
+}
+
+func TestSyncPlanTimeUnits(t *testing.T) {
+	server := zabbixtest.Server(t, map[string]interface{}{
+		"APIInfo.version": "5.0.0",
+		"host.get":        []interface{}{map[string]interface{}{"hostid": "10100", "host": "web1", "status": "0"}},
+		"application.get": []interface{}{},
+		"item.get": []interface{}{map[string]interface{}{
+			"itemid": "23000", "hostid": "10100", "key_": "agent.ping", "name": "Ping", "type": "0", "value_type": "3",
+			"delay": "60s", "history": "1w", "trends": "365d", "templateid": "0", "flags": "0",
+		}},
+	})
+	defer server.Close()
+	api := NewAPI(server.URL)
+
+	state := &DesiredState{Hosts: []DesiredHost{{
+		Host:  Host{Host: "web1"},
+		Items: []DesiredItem{{Item: Item{Key: "agent.ping", Name: "Ping", ValueType: Unsigned}, Delay: "1m", History: "7d", Trends: "365d"}},
+	}}}
+	for delay, expected := range map[string]string{
+		"1m":       "",
+		"60":       "",
+		"30s":      "~ item web1/agent.ping (delay)\n",
+		"{$DELAY}": "~ item web1/agent.ping (delay)\n",
+	} {
+		state.Hosts[0].Items[0].Delay = delay
+		plan, err := api.SyncPlan(state, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.String() != expected {
+			t.Errorf("%s: bad plan:\n%s\nexpected:\n%s", delay, plan, expected)
+		}
+	}
+
+	state.Hosts[0].Items[0].Delay = "1m"
+	state.Hosts[0].Items[0].History = "90d"
+	plan, err := api.SyncPlan(state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "~ item web1/agent.ping (history)\n"; plan.String() != expected {
+		t.Errorf("Bad plan:\n%s\nexpected:\n%s", plan, expected)
+	}
+}
+
+func TestSyncPlanDuplicates(t *testing.T) {
+	api := getAPI(t)
+	state := &DesiredState{Hosts: []DesiredHost{{
+		Host:  Host{Host: "web1"},
+		Items: []DesiredItem{{Item: Item{Key: "agent.ping"}}, {Item: Item{Key: "agent.ping"}}},
+	}}}
+	_, err := api.SyncPlan(state, false)
+	if !reflect.DeepEqual(err, &SyncDuplicate{SyncItem, "web1/agent.ping"}) {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestSync(t *testing.T) {
+	api := getAPI(t)
+
+	state := &DesiredState{
+		HostGroups: []string{"lala sync group"},
+		Hosts: []DesiredHost{{
+			Host:         Host{Host: "lala-sync-host", Interfaces: HostInterfaces{{IP: "127.0.0.1", Main: 1, Port: "10050", Type: Agent, UseIP: 1}}},
+			Groups:       []string{"lala sync group"},
+			Applications: []string{"lala app"},
+			Items: []DesiredItem{
+				{Item: Item{Key: "agent.ping", Name: "Ping", Type: ZabbixAgent, ValueType: Unsigned}, Delay: "60", Applications: []string{"lala app"}},
+				{Item: Item{Key: "lala.trap", Name: "Trap", Type: ZabbixTrapper, ValueType: Text}},
+			},
+		}},
+	}
+
+	plan, err := api.SyncPlan(state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("Plan:\n%s", plan)
+	if len(plan.Changes) != 5 || plan.Changes[0].Kind != SyncHostGroup || plan.Changes[4].Kind != SyncItem {
+		t.Fatalf("Bad plan:\n%s", plan)
+	}
+	err = api.SyncApply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, err := api.HostGetByHost("lala-sync-host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer api.HostGroupsDeleteByIds([]string{func() string {
+		groups, _ := api.HostGroupsGet(Params{"filter": map[string]string{"name": "lala sync group"}})
+		return groups[0].GroupId
+	}()})
+	defer api.HostsDeleteByIds([]string{host.HostId})
+
+	items, err := api.ItemsGet(Params{"hostids": host.HostId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items.ByKey()["agent.ping"].InterfaceId == "" {
+		t.Errorf("Bad items: %#v", items)
+	}
+
+	plan, err = api.SyncPlan(state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+
+	state.Hosts[0].Items = state.Hosts[0].Items[:1]
+	state.Hosts[0].Items[0].Item.Name = "Agent ping"
+	plan, err = api.SyncPlan(state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "~ item lala-sync-host/agent.ping (name)\n- item lala-sync-host/lala.trap\n"
+	if plan.String() != expected {
+		t.Errorf("Bad plan:\n%s\nexpected:\n%s", plan, expected)
+	}
+	err = api.SyncApply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err = api.ItemsGet(Params{"hostids": host.HostId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Name != "Agent ping" {
+		t.Errorf("Bad items: %#v", items)
+	}
+}
+
+func TestSyncTemplates(t *testing.T) {
+	api := getAPI(t)
+
+	state := &DesiredState{
+		HostGroups: []string{"lala sync templates"},
+		Templates:  []DesiredTemplate{{Template: Template{Host: "lala sync template"}, Groups: []string{"lala sync templates"}}},
+		Hosts: []DesiredHost{{
+			Host:      Host{Host: "lala-sync-templated"},
+			Groups:    []string{"lala sync templates"},
+			Templates: []string{"lala sync template"},
+		}},
+	}
+
+	plan, err := api.SyncPlan(state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "+ host group lala sync templates\n+ template lala sync template\n+ host lala-sync-templated\n"
+	if plan.String() != expected {
+		t.Fatalf("Bad plan:\n%s\nexpected:\n%s", plan, expected)
+	}
+	err = api.SyncApply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := api.TemplatesGet(Params{"filter": map[string]string{"host": "lala sync template"}}, SelectGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || len(templates[0].GroupIds) != 1 {
+		t.Fatalf("Bad templates: %#v", templates)
+	}
+	template := templates[0]
+	host, err := api.HostGetByHost("lala-sync-templated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer api.HostGroupsDeleteByIds([]string{template.GroupIds[0].GroupId})
+	defer api.TemplatesDeleteByIds([]string{template.TemplateId})
+	defer api.HostsDeleteByIds([]string{host.HostId})
+
+	hosts, err := api.HostsGet(Params{"hostids": host.HostId}, SelectParentTemplates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts[0].Templates) != 1 || hosts[0].Templates[0].TemplateId != template.TemplateId {
+		t.Errorf("Bad templates: %#v", hosts[0].Templates)
+	}
+
+	state.Templates[0].Template.Description = "Managed by sync"
+	plan, err = api.SyncPlan(state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected = "~ template lala sync template (description)\n"; plan.String() != expected {
+		t.Errorf("Bad plan:\n%s\nexpected:\n%s", plan, expected)
+	}
+
+	state.Hosts[0].Templates = []string{"lala missing template"}
+	_, err = api.SyncPlan(state, false)
+	if !reflect.DeepEqual(err, &SyncUnresolved{SyncTemplate, "lala missing template", "lala-sync-templated"}) {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
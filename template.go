@@ -0,0 +1,146 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/definitions
+type Template struct {
+	TemplateId  string `json:"templateid,omitempty"`
+	Host        string `json:"host"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Fields below are sent when creating and updating templates and filled by TemplatesGet
+	// when corresponding related objects are selected.
+	GroupIds  HostGroupIds `json:"groups,omitempty"`
+	Templates TemplateIds  `json:"templates,omitempty"`
+	Macros    UserMacros   `json:"macros,omitempty"`
+}
+
+type Templates []Template
+
+// Wrapper for template.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/get
+// Given related objects (SelectGroups, SelectParentTemplates or SelectMacros) are requested
+// with "extend" output unless params already contain select option.
+func (api *API) TemplatesGet(params Params, selects ...Select) (res Templates, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	params.addSelects(selects)
+	response, err := api.CallWithError("template.get", params)
+	if err != nil {
+		return
+	}
+
+	// parent templates are sent as "templates" on create
+	result := response.Result.([]interface{})
+	for _, r := range result {
+		m := r.(map[string]interface{})
+		if templates, present := m["parentTemplates"]; present {
+			m["templates"] = templates
+		}
+	}
+	decodeResult(result, &res)
+	return
+}
+
+// Gets template by Id only if there is exactly 1 matching template.
+func (api *API) TemplateGetById(id string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"templateids": id})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Gets template by technical name only if there is exactly 1 matching template.
+func (api *API) TemplateGetByHost(host string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"filter": map[string]string{"host": host}})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for template.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/create
+func (api *API) TemplatesCreate(templates Templates) (err error) {
+	response, err := api.CallWithError("template.create", templates)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	for i, id := range templateids {
+		templates[i].TemplateId = id.(string)
+	}
+	return
+}
+
+// Wrapper for template.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/update
+// Only given fields (named as in JSON, like "name" or "groups") are sent along with templateid;
+// all fields are sent if none given. Unknown field names are reported as error.
+func (api *API) TemplatesUpdate(templates Templates, fields ...string) (err error) {
+	update := make([]Params, len(templates))
+	for i, template := range templates {
+		update[i], err = updateParams(template, "templateid", nil, fields)
+		if err != nil {
+			return
+		}
+	}
+
+	response, err := api.CallWithError("template.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	if len(templates) != len(templateids) {
+		err = &ExpectedMore{len(templates), len(templateids)}
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/delete
+// Cleans TemplateId in all templates elements if call succeed.
+func (api *API) TemplatesDelete(templates Templates) (err error) {
+	ids := make([]string, len(templates))
+	for i, template := range templates {
+		ids[i] = template.TemplateId
+	}
+
+	err = api.TemplatesDeleteByIds(ids)
+	if err == nil {
+		for i := range templates {
+			templates[i].TemplateId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/template/delete
+func (api *API) TemplatesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("template.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	if len(ids) != len(templateids) {
+		err = &ExpectedMore{len(ids), len(templateids)}
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package zabbix_test
+
+import (
+	. "."
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func CreateTemplate(hostGroup *HostGroup, t *testing.T) *Template {
+	templates := Templates{{
+		Host:     fmt.Sprintf("template-testing-%d", rand.Int()),
+		GroupIds: HostGroupIds{{hostGroup.GroupId}},
+	}}
+	err := getAPI(t).TemplatesCreate(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &templates[0]
+}
+
+func DeleteTemplate(template *Template, t *testing.T) {
+	err := getAPI(t).TemplatesDelete(Templates{*template})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	template := CreateTemplate(group, t)
+	if template.TemplateId == "" || template.Host == "" {
+		t.Errorf("Something is empty: %#v", template)
+	}
+
+	template.Name = "Updated " + template.Host
+	err := api.TemplatesUpdate(Templates{*template}, "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = api.TemplatesUpdate(Templates{*template}, "nmae")
+	if err == nil || err.Error() != `Unknown field "nmae".` {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+
+	template2, err := api.TemplateGetByHost(template.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template2.TemplateId != template.TemplateId || template2.Name != template.Name {
+		t.Errorf("Error getting template.\nOld template: %#v\nNew template: %#v", template, template2)
+	}
+
+	templates, err := api.TemplatesGet(Params{"templateids": template.TemplateId}, SelectGroups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || len(templates[0].GroupIds) != 1 || templates[0].GroupIds[0].GroupId != group.GroupId {
+		t.Errorf("Bad templates: %#v", templates)
+	}
+
+	DeleteTemplate(template, t)
+	if template.TemplateId != "" {
+		t.Errorf("Id is not cleaned: %#v", template)
+	}
+	_, err = api.TemplateGetByHost(templates[0].Host)
+	if _, ok := err.(*ExpectedOneResult); !ok {
+		t.Errorf("Expected ExpectedOneResult error, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package zabbix
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Configures discovery of links between hosts from interface alias (description) items.
+// Each alias item whose value names a neighbor host becomes a link.
+type TopologyConfig struct {
+	// Only items with keys containing this string are requested. All items are requested if empty.
+	KeySearch string
+
+	// Keys of alias items; first submatch is interface name.
+	AliasKey *regexp.Regexp
+
+	// Alias items with keys matching any of these are skipped.
+	IgnoreKeys []*regexp.Regexp
+
+	// Alias values matching any of these are not links (like "0" or descriptions of user ports).
+	IgnoreAliases []*regexp.Regexp
+
+	// Extracts neighbor name from alias value: first submatch if regexp has groups, whole match otherwise.
+	// Aliases not matching are not links.
+	Neighbor *regexp.Regexp
+
+	// Extracts neighbor interface from alias value, the same way as Neighbor. Optional.
+	NeighborInterface *regexp.Regexp
+
+	// Applied to extracted neighbor names in order, e.g. to merge cluster members into one node.
+	NeighborRewrites []NeighborRewrite
+
+	// If host has several links to the same neighbor and some of them have interfaces matching this regexp,
+	// only those links are kept; e.g. link aggregation interface is preferred to its member ports. Optional.
+	PreferInterfaces *regexp.Regexp
+}
+
+// Replaces neighbor name matching Pattern with Replacement, which may refer to submatches like $1.
+type NeighborRewrite struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// Returns config for items like alias[GigabitEthernet0/1] with values starting with neighbor name.
+func DefaultTopologyConfig() *TopologyConfig {
+	return &TopologyConfig{
+		KeySearch: "alias",
+		AliasKey:  regexp.MustCompile(`^alias\[(.+)\]$`),
+		Neighbor:  regexp.MustCompile(`^(\S+)`),
+	}
+}
+
+// Link from interface of known host to neighbor.
+type TopologyLink struct {
+	HostId            string
+	Host              string
+	Interface         string
+	ItemId            string // id of alias item
+	Alias             string
+	Neighbor          string
+	NeighborHostId    string // empty if neighbor is not known host
+	NeighborInterface string
+}
+
+// Graph of hosts and links between them. Links are sorted by host, neighbor and interface.
+type Topology struct {
+	Hosts Hosts
+	Links []TopologyLink
+}
+
+// Returns names of neighbors of given host, sorted.
+func (t *Topology) Neighbors(hostId string) (neighbors []string) {
+	seen := make(map[string]bool)
+	for _, link := range t.Links {
+		if link.HostId == hostId && !seen[link.Neighbor] {
+			seen[link.Neighbor] = true
+			neighbors = append(neighbors, link.Neighbor)
+		}
+	}
+	sort.Strings(neighbors)
+	return
+}
+
+// Returns links of given host to neighbor.
+func (t *Topology) LinksBetween(hostId, neighbor string) (links []TopologyLink) {
+	for _, link := range t.Links {
+		if link.HostId == hostId && link.Neighbor == neighbor {
+			links = append(links, link)
+		}
+	}
+	return
+}
+
+// Builds topology of given hosts from their alias items.
+// Items values are taken from LastValue, or PrevValue if LastValue is empty.
+// Neighbors are matched to hosts by technical or visible name, case-insensitively.
+func (c *TopologyConfig) Build(hosts Hosts, items Items) *Topology {
+	byId := make(map[string]*Host, len(hosts))
+	byName := make(map[string]*Host, len(hosts)*2)
+	for i := range hosts {
+		h := &hosts[i]
+		byId[h.HostId] = h
+		byName[strings.ToLower(h.Host)] = h
+		if h.Name != "" {
+			byName[strings.ToLower(h.Name)] = h
+		}
+	}
+
+	var links []TopologyLink
+	for _, item := range items {
+		host := byId[item.HostId]
+		if host == nil {
+			continue
+		}
+		link, ok := c.link(item)
+		if !ok {
+			continue
+		}
+		link.HostId, link.Host = host.HostId, host.Host
+		if neighbor := byName[strings.ToLower(link.Neighbor)]; neighbor != nil {
+			link.NeighborHostId, link.Neighbor = neighbor.HostId, neighbor.Host
+		}
+		links = append(links, link)
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		a, b := links[i], links[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Neighbor != b.Neighbor {
+			return a.Neighbor < b.Neighbor
+		}
+		return a.Interface < b.Interface
+	})
+	return &Topology{Hosts: hosts, Links: c.preferred(links)}
+}
+
+func (c *TopologyConfig) link(item Item) (link TopologyLink, ok bool) {
+	m := c.AliasKey.FindStringSubmatch(item.Key)
+	if m == nil || matchesAny(c.IgnoreKeys, item.Key) {
+		return
+	}
+	alias := item.LastValue
+	if alias == "" {
+		alias = item.PrevValue
+	}
+	alias = strings.TrimSpace(alias)
+	if alias == "" || matchesAny(c.IgnoreAliases, alias) {
+		return
+	}
+	neighbor := extractSubmatch(c.Neighbor, alias)
+	if neighbor == "" {
+		return
+	}
+	for _, r := range c.NeighborRewrites {
+		if r.Pattern.MatchString(neighbor) {
+			neighbor = r.Pattern.ReplaceAllString(neighbor, r.Replacement)
+		}
+	}
+
+	link = TopologyLink{ItemId: item.ItemId, Alias: alias, Neighbor: neighbor, Interface: item.Key}
+	if len(m) > 1 {
+		link.Interface = m[1]
+	}
+	if c.NeighborInterface != nil {
+		link.NeighborInterface = extractSubmatch(c.NeighborInterface, alias)
+	}
+	return link, true
+}
+
+// Filters sorted links by PreferInterfaces.
+func (c *TopologyConfig) preferred(links []TopologyLink) (res []TopologyLink) {
+	if c.PreferInterfaces == nil {
+		return links
+	}
+	for start := 0; start < len(links); {
+		end := start + 1
+		for end < len(links) && links[end].HostId == links[start].HostId && links[end].Neighbor == links[start].Neighbor {
+			end++
+		}
+		var preferred []TopologyLink
+		for _, link := range links[start:end] {
+			if c.PreferInterfaces.MatchString(link.Interface) {
+				preferred = append(preferred, link)
+			}
+		}
+		if preferred == nil {
+			preferred = links[start:end]
+		}
+		res = append(res, preferred...)
+		start = end
+	}
+	return
+}
+
+func matchesAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func extractSubmatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	if len(m) > 1 {
+		return m[1]
+	}
+	if len(m) == 1 {
+		return m[0]
+	}
+	return ""
+}
+
+// Discovers topology of hosts selected by params for host.get (like groupids).
+// Uses DefaultTopologyConfig if config is nil.
+func (api *API) TopologyGet(config *TopologyConfig, params Params) (topology *Topology, err error) {
+	if config == nil {
+		config = DefaultTopologyConfig()
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = []string{"hostid", "host", "name"}
+	}
+	hosts, err := api.HostsGet(params)
+	if err != nil {
+		return
+	}
+
+	ids := make([]string, len(hosts))
+	for i, host := range hosts {
+		ids[i] = host.HostId
+	}
+	itemParams := Params{
+		"hostids": ids,
+		"output":  []string{"itemid", "hostid", "key_", "lastvalue", "prevvalue"},
+	}
+	if config.KeySearch != "" {
+		itemParams["search"] = map[string]string{"key_": config.KeySearch}
+	}
+	items, err := api.ItemsGet(itemParams)
+	if err != nil {
+		return
+	}
+
+	topology = config.Build(hosts, items)
+	return
+}
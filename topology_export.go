@@ -0,0 +1,192 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Node of topology: known host or neighbor which is not a known host.
+type TopologyNode struct {
+	Name     string `json:"name"`
+	HostId   string `json:"hostid,omitempty"`
+	External bool   `json:"external,omitempty"`
+}
+
+// Physical link between two nodes. Links seen from both sides are merged into one edge.
+type TopologyEdge struct {
+	From          string   `json:"from"`
+	FromInterface string   `json:"from_interface,omitempty"`
+	To            string   `json:"to"`
+	ToInterface   string   `json:"to_interface,omitempty"`
+	ItemIds       []string `json:"itemids"` // ids of alias items describing this edge
+}
+
+// Returns known hosts sorted by name, followed by external neighbors sorted by name.
+func (t *Topology) Nodes() (nodes []TopologyNode) {
+	for _, host := range t.Hosts {
+		nodes = append(nodes, TopologyNode{Name: host.Host, HostId: host.HostId})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	var external []TopologyNode
+	seen := make(map[string]bool)
+	for _, link := range t.Links {
+		if link.NeighborHostId == "" && !seen[link.Neighbor] {
+			seen[link.Neighbor] = true
+			external = append(external, TopologyNode{Name: link.Neighbor, External: true})
+		}
+	}
+	sort.Slice(external, func(i, j int) bool { return external[i].Name < external[j].Name })
+	return append(nodes, external...)
+}
+
+// Returns edges in links order. Link from A to B is merged with link from B to A
+// if interfaces they report for each other do not contradict.
+func (t *Topology) Edges() (edges []TopologyEdge) {
+	merged := make([]bool, len(t.Links))
+	for i, a := range t.Links {
+		if merged[i] {
+			continue
+		}
+		edge := TopologyEdge{From: a.Host, FromInterface: a.Interface, To: a.Neighbor, ToInterface: a.NeighborInterface, ItemIds: []string{a.ItemId}}
+		for j := i + 1; j < len(t.Links); j++ {
+			b := t.Links[j]
+			if merged[j] || b.HostId != a.NeighborHostId || b.Neighbor != a.Host {
+				continue
+			}
+			if (a.NeighborInterface != "" && a.NeighborInterface != b.Interface) || (b.NeighborInterface != "" && b.NeighborInterface != a.Interface) {
+				continue
+			}
+			merged[j] = true
+			edge.ToInterface = b.Interface
+			edge.ItemIds = append(edge.ItemIds, b.ItemId)
+			break
+		}
+		edges = append(edges, edge)
+	}
+	return
+}
+
+// Returns topology as Graphviz DOT undirected graph. Interfaces are used as edge end labels.
+func (t *Topology) DOT(name string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "graph %s {\n", dotQuote(name))
+	for _, node := range t.Nodes() {
+		if node.External {
+			fmt.Fprintf(&b, "\t%s [style=dashed];\n", dotQuote(node.Name))
+		} else {
+			fmt.Fprintf(&b, "\t%s;\n", dotQuote(node.Name))
+		}
+	}
+	for _, edge := range t.Edges() {
+		fmt.Fprintf(&b, "\t%s -- %s [taillabel=%s, headlabel=%s];\n",
+			dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.FromInterface), dotQuote(edge.ToInterface))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// Returns topology as JSON document with "nodes" and "edges" arrays.
+func (t *Topology) JSON() ([]byte, error) {
+	doc := struct {
+		Nodes []TopologyNode `json:"nodes"`
+		Edges []TopologyEdge `json:"edges"`
+	}{t.Nodes(), t.Edges()}
+	if doc.Nodes == nil {
+		doc.Nodes = []TopologyNode{}
+	}
+	if doc.Edges == nil {
+		doc.Edges = []TopologyEdge{}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// Options of map generated from topology.
+type TopologyMapOptions struct {
+	Width          int    // 800 by default
+	Height         int    // 600 by default
+	HostIconId     string // image id for host elements, required
+	ExternalIconId string // image id for external neighbors; they are skipped if empty
+}
+
+// Returns map with nodes placed on a circle and links for edges between them.
+// Returns error if HostIconId is not set, as map.create requires icon of every element.
+func (t *Topology) Map(name string, options TopologyMapOptions) (*Map, error) {
+	if options.HostIconId == "" {
+		return nil, fmt.Errorf("HostIconId is required.")
+	}
+	if options.Width <= 0 {
+		options.Width = 800
+	}
+	if options.Height <= 0 {
+		options.Height = 600
+	}
+
+	var nodes []TopologyNode
+	for _, node := range t.Nodes() {
+		if !node.External || options.ExternalIconId != "" {
+			nodes = append(nodes, node)
+		}
+	}
+
+	// element ids are temporary, they are used by links in the same request
+	m := &Map{Name: name, Width: options.Width, Height: options.Height, Elements: make(MapElements, len(nodes))}
+	ids := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		ids[node.Name] = fmt.Sprint(i + 1)
+		element := MapElement{SelementId: ids[node.Name]}
+		element.X, element.Y = circlePosition(i, len(nodes), options.Width, options.Height)
+		if node.External {
+			element.ElementType = MapElementImage
+			element.Label = node.Name
+			element.IconIdOff = options.ExternalIconId
+		} else {
+			element.ElementType = MapElementHost
+			element.Elements = MapElementObjects{{HostId: node.HostId}}
+			element.Label = "{HOST.NAME}"
+			element.IconIdOff = options.HostIconId
+		}
+		m.Elements[i] = element
+	}
+
+	for _, edge := range t.Edges() {
+		from, to := ids[edge.From], ids[edge.To]
+		if from == "" || to == "" {
+			continue
+		}
+		m.Links = append(m.Links, MapLink{SelementId1: from, SelementId2: to, Label: edgeLabel(edge)})
+	}
+	return m, nil
+}
+
+func edgeLabel(edge TopologyEdge) string {
+	var parts []string
+	for _, s := range []string{edge.FromInterface, edge.ToInterface} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " - ")
+}
+
+// Returns position of i-th of n elements placed evenly on a circle, starting from the top.
+func circlePosition(i, n, width, height int) (x, y int) {
+	const margin = 60
+	if n == 1 {
+		return width / 2, height / 2
+	}
+	r := float64(width)/2 - margin
+	if height < width {
+		r = float64(height)/2 - margin
+	}
+	angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+	return width/2 + int(math.Round(r*math.Cos(angle))), height/2 + int(math.Round(r*math.Sin(angle)))
+}
@@ -0,0 +1,101 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testTopology() *Topology {
+	return &Topology{
+		Hosts: Hosts{{HostId: "2", Host: "sw2"}, {HostId: "1", Host: "sw1"}},
+		Links: []TopologyLink{
+			{HostId: "1", Host: "sw1", Interface: "Gi0/1", ItemId: "10", Neighbor: "internet"},
+			{HostId: "1", Host: "sw1", Interface: "Gi0/2", ItemId: "11", Neighbor: "sw2", NeighborHostId: "2", NeighborInterface: "Gi0/24"},
+			{HostId: "2", Host: "sw2", Interface: "Gi0/23", ItemId: "20", Neighbor: "sw1", NeighborHostId: "1"},
+			{HostId: "2", Host: "sw2", Interface: "Gi0/24", ItemId: "21", Neighbor: "sw1", NeighborHostId: "1", NeighborInterface: "Gi0/2"},
+		},
+	}
+}
+
+func TestTopologyEdges(t *testing.T) {
+	topology := testTopology()
+	nodes := topology.Nodes()
+	expectedNodes := []TopologyNode{{Name: "sw1", HostId: "1"}, {Name: "sw2", HostId: "2"}, {Name: "internet", External: true}}
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("Bad nodes:\n%#v\n%#v", nodes, expectedNodes)
+	}
+
+	edges := topology.Edges()
+	expectedEdges := []TopologyEdge{
+		{From: "sw1", FromInterface: "Gi0/1", To: "internet", ItemIds: []string{"10"}},
+		{From: "sw1", FromInterface: "Gi0/2", To: "sw2", ToInterface: "Gi0/24", ItemIds: []string{"11", "21"}},
+		{From: "sw2", FromInterface: "Gi0/23", To: "sw1", ItemIds: []string{"20"}},
+	}
+	if !reflect.DeepEqual(edges, expectedEdges) {
+		t.Errorf("Bad edges:\n%#v\n%#v", edges, expectedEdges)
+	}
+}
+
+func TestTopologyDOT(t *testing.T) {
+	expected := `graph "lab \"1\"" {
+	"sw1";
+	"sw2";
+	"internet" [style=dashed];
+	"sw1" -- "internet" [taillabel="Gi0/1", headlabel=""];
+	"sw1" -- "sw2" [taillabel="Gi0/2", headlabel="Gi0/24"];
+	"sw2" -- "sw1" [taillabel="Gi0/23", headlabel=""];
+}
+`
+	if dot := testTopology().DOT(`lab "1"`); dot != expected {
+		t.Errorf("Bad DOT:\n%s\nexpected:\n%s", dot, expected)
+	}
+}
+
+func TestTopologyJSON(t *testing.T) {
+	b, err := testTopology().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Nodes []map[string]interface{}
+		Edges []map[string]interface{}
+	}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Nodes) != 3 || len(doc.Edges) != 3 || doc.Edges[1]["to_interface"] != "Gi0/24" || doc.Nodes[2]["external"] != true {
+		t.Errorf("Bad JSON document: %s", b)
+	}
+
+	b, err = (&Topology{}).JSON()
+	if err != nil || string(b) != "{\n  \"nodes\": [],\n  \"edges\": []\n}" {
+		t.Errorf("Bad empty JSON document: %s, %v", b, err)
+	}
+}
+
+func TestTopologyMap(t *testing.T) {
+	m, err := testTopology().Map("lab", TopologyMapOptions{HostIconId: "2", ExternalIconId: "3", Width: 400, Height: 400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "lab" || len(m.Elements) != 3 || len(m.Links) != 3 {
+		t.Fatalf("Bad map: %#v", m)
+	}
+	e := m.Elements[1]
+	if e.ElementType != MapElementHost || e.Elements[0].HostId != "2" || e.IconIdOff != "2" || e.SelementId != "2" {
+		t.Errorf("Bad element: %#v", e)
+	}
+	if e = m.Elements[2]; e.ElementType != MapElementImage || e.Label != "internet" || e.Elements != nil {
+		t.Errorf("Bad image element: %#v", e)
+	}
+	expected := MapLink{SelementId1: "1", SelementId2: "3", Label: "Gi0/1"}
+	if !reflect.DeepEqual(m.Links[0], expected) {
+		t.Errorf("Bad link: %#v", m.Links[0])
+	}
+
+	if _, err = testTopology().Map("lab", TopologyMapOptions{}); err == nil {
+		t.Error("Expected error for missing HostIconId")
+	}
+}
@@ -0,0 +1,53 @@
+package zabbix_test
+
+import (
+	. "."
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestTopologyBuild(t *testing.T) {
+	config := DefaultTopologyConfig()
+	config.IgnoreKeys = []*regexp.Regexp{regexp.MustCompile(`^alias_(admin|prod)\[`)}
+	config.IgnoreAliases = []*regexp.Regexp{regexp.MustCompile(`^0$|Portable`)}
+	config.Neighbor = regexp.MustCompile(`^(\S+?)(?:_(\S+))?$`)
+	config.NeighborInterface = regexp.MustCompile(`_(\S+)$`)
+	config.NeighborRewrites = []NeighborRewrite{{regexp.MustCompile(`^PRDNETRHP\d+$`), "PRDNETRHP500"}}
+	config.PreferInterfaces = regexp.MustCompile(`^Aggregation`)
+
+	hosts := Hosts{{HostId: "1", Host: "sw1"}, {HostId: "2", Host: "sw2", Name: "Switch 2"}}
+	items := Items{
+		{ItemId: "10", HostId: "1", Key: "alias[GigabitEthernet0/1]", LastValue: "SW2_Gi0/1"},
+		{ItemId: "11", HostId: "1", Key: "alias[GigabitEthernet0/2]", LastValue: "SW2_Gi0/2"},
+		{ItemId: "12", HostId: "1", Key: "alias[Aggregation1]", PrevValue: "sw2_Agg1"},
+		{ItemId: "13", HostId: "1", Key: "alias_admin[GigabitEthernet0/3]", LastValue: "sw2"},
+		{ItemId: "14", HostId: "1", Key: "alias[GigabitEthernet0/4]", LastValue: "0"},
+		{ItemId: "15", HostId: "1", Key: "alias[GigabitEthernet0/5]", LastValue: "PRDNETRHP512"},
+		{ItemId: "16", HostId: "1", Key: "ifAlias[1]", LastValue: "sw2"},
+		{ItemId: "20", HostId: "2", Key: "alias[Gi0/1]", LastValue: "sw1_GigabitEthernet0/1"},
+		{ItemId: "30", HostId: "3", Key: "alias[Gi0/1]", LastValue: "sw1"},
+	}
+
+	topology := config.Build(hosts, items)
+	expected := []TopologyLink{
+		{HostId: "1", Host: "sw1", Interface: "GigabitEthernet0/5", ItemId: "15", Alias: "PRDNETRHP512", Neighbor: "PRDNETRHP500"},
+		{HostId: "1", Host: "sw1", Interface: "Aggregation1", ItemId: "12", Alias: "sw2_Agg1", Neighbor: "sw2", NeighborHostId: "2", NeighborInterface: "Agg1"},
+		{HostId: "2", Host: "sw2", Interface: "Gi0/1", ItemId: "20", Alias: "sw1_GigabitEthernet0/1", Neighbor: "sw1", NeighborHostId: "1", NeighborInterface: "GigabitEthernet0/1"},
+	}
+	if !reflect.DeepEqual(topology.Links, expected) {
+		t.Errorf("Bad links:\n%#v\n%#v", topology.Links, expected)
+	}
+
+	if n := topology.Neighbors("1"); !reflect.DeepEqual(n, []string{"PRDNETRHP500", "sw2"}) {
+		t.Errorf("Bad neighbors: %v", n)
+	}
+	if l := topology.LinksBetween("2", "sw1"); len(l) != 1 || l[0].ItemId != "20" {
+		t.Errorf("Bad links between: %#v", l)
+	}
+
+	config.PreferInterfaces = nil
+	if l := config.Build(hosts, items).LinksBetween("1", "sw2"); len(l) != 3 {
+		t.Errorf("Expected all links without preference, got %#v", l)
+	}
+}
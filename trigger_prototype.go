@@ -0,0 +1,123 @@
+package zabbix
+
+type (
+	SeverityType int
+)
+
+const (
+	NotClassified SeverityType = 0
+	Information   SeverityType = 1
+	Warning       SeverityType = 2
+	Average       SeverityType = 3
+	High          SeverityType = 4
+	Disaster      SeverityType = 5
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/triggerprototype/definitions
+// Description is trigger name.
+type TriggerPrototype struct {
+	TriggerId   string       `json:"triggerid,omitempty"`
+	Description string       `json:"description"`
+	Expression  string       `json:"expression"`
+	Priority    SeverityType `json:"priority"`
+	Status      StatusType   `json:"status"`
+	Comments    string       `json:"comments,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	TemplateId  string       `json:"templateid,omitempty"`
+
+	Tags HostTags `json:"tags,omitempty"` // Zabbix 3.2+
+}
+
+type TriggerPrototypes []TriggerPrototype
+
+// Wrapper for triggerprototype.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/triggerprototype/get
+// Expression is returned with item keys instead of function Ids.
+func (api *API) TriggerPrototypesGet(params Params) (res TriggerPrototypes, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["expandExpression"]; !present {
+		params["expandExpression"] = true
+	}
+	response, err := api.CallWithError("triggerprototype.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets trigger prototypes by discovery rule Id.
+func (api *API) TriggerPrototypesGetByRuleId(id string) (res TriggerPrototypes, err error) {
+	return api.TriggerPrototypesGet(Params{"discoveryids": id})
+}
+
+// Wrapper for triggerprototype.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/triggerprototype/create
+func (api *API) TriggerPrototypesCreate(prototypes TriggerPrototypes) (err error) {
+	response, err := api.CallWithError("triggerprototype.create", prototypes)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	for i, id := range triggerids {
+		prototypes[i].TriggerId = id.(string)
+	}
+	return
+}
+
+// Wrapper for triggerprototype.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/triggerprototype/update
+// TemplateId is not sent as it can't be changed.
+func (api *API) TriggerPrototypesUpdate(prototypes TriggerPrototypes) (err error) {
+	update := make(TriggerPrototypes, len(prototypes))
+	for i, prototype := range prototypes {
+		prototype.TemplateId = ""
+		update[i] = prototype
+	}
+
+	response, err := api.CallWithError("triggerprototype.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	if len(prototypes) != len(triggerids) {
+		err = &ExpectedMore{len(prototypes), len(triggerids)}
+	}
+	return
+}
+
+// Wrapper for triggerprototype.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/triggerprototype/delete
+// Cleans TriggerId in all prototypes elements if call succeed.
+func (api *API) TriggerPrototypesDelete(prototypes TriggerPrototypes) (err error) {
+	ids := make([]string, len(prototypes))
+	for i, prototype := range prototypes {
+		ids[i] = prototype.TriggerId
+	}
+
+	err = api.TriggerPrototypesDeleteByIds(ids)
+	if err == nil {
+		for i := range prototypes {
+			prototypes[i].TriggerId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for triggerprototype.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/triggerprototype/delete
+func (api *API) TriggerPrototypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("triggerprototype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	if len(ids) != len(triggerids) {
+		err = &ExpectedMore{len(ids), len(triggerids)}
+	}
+	return
+}
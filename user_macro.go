@@ -0,0 +1,374 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	MacroType int
+)
+
+const (
+	TextMacro   MacroType = 0
+	SecretMacro MacroType = 1 // value is never returned by API, Zabbix 5.0+
+	VaultMacro  MacroType = 2 // value is a path to vault secret, Zabbix 5.2+
+)
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/definitions
+// Type is not sent if nil, as servers before 5.0 don't accept it; set it to pointer to TextMacro
+// to change secret or vault macro back to text one.
+type UserMacro struct {
+	HostMacroId string     `json:"hostmacroid,omitempty"`
+	HostId      string     `json:"hostid,omitempty"`
+	Macro       string     `json:"macro"`
+	Value       string     `json:"value"`
+	Type        *MacroType `json:"type,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+type UserMacros []UserMacro
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/definitions
+// Type is handled as in UserMacro.
+type GlobalMacro struct {
+	GlobalMacroId string     `json:"globalmacroid,omitempty"`
+	Macro         string     `json:"macro"`
+	Value         string     `json:"value"`
+	Type          *MacroType `json:"type,omitempty"`
+	Description   string     `json:"description,omitempty"`
+}
+
+type GlobalMacros []GlobalMacro
+
+type MacroNotFound string
+
+func (e MacroNotFound) Error() string {
+	return fmt.Sprintf("Macro %s is not defined.", string(e))
+}
+
+// Wrapper for usermacro.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/get
+func (api *API) UserMacrosGet(params Params) (res UserMacros, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("usermacro.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Gets macros defined directly on given hosts or templates.
+func (api *API) UserMacrosGetByHostIds(ids []string) (res UserMacros, err error) {
+	return api.UserMacrosGet(Params{"hostids": ids})
+}
+
+// Wrapper for usermacro.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/create
+func (api *API) UserMacrosCreate(macros UserMacros) (err error) {
+	response, err := api.CallWithError("usermacro.create", macros)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostmacroids := result["hostmacroids"].([]interface{})
+	for i, id := range hostmacroids {
+		macros[i].HostMacroId = id.(string)
+	}
+	return
+}
+
+// Wrapper for usermacro.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/update
+// HostId is not sent as macro can't be moved to another host.
+func (api *API) UserMacrosUpdate(macros UserMacros) (err error) {
+	update := make(UserMacros, len(macros))
+	for i, macro := range macros {
+		macro.HostId = ""
+		update[i] = macro
+	}
+
+	response, err := api.CallWithError("usermacro.update", update)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostmacroids := result["hostmacroids"].([]interface{})
+	if len(macros) != len(hostmacroids) {
+		err = &ExpectedMore{len(macros), len(hostmacroids)}
+	}
+	return
+}
+
+// Wrapper for usermacro.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/delete
+// Cleans HostMacroId in all macros elements if call succeed.
+func (api *API) UserMacrosDelete(macros UserMacros) (err error) {
+	ids := make([]string, len(macros))
+	for i, macro := range macros {
+		ids[i] = macro.HostMacroId
+	}
+
+	err = api.UserMacrosDeleteByIds(ids)
+	if err == nil {
+		for i := range macros {
+			macros[i].HostMacroId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for usermacro.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/delete
+func (api *API) UserMacrosDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("usermacro.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	hostmacroids := result["hostmacroids"].([]interface{})
+	if len(ids) != len(hostmacroids) {
+		err = &ExpectedMore{len(ids), len(hostmacroids)}
+	}
+	return
+}
+
+// Wrapper for usermacro.get with globalmacro flag: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/get
+func (api *API) GlobalMacrosGet(params Params) (res GlobalMacros, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	params["globalmacro"] = true
+	response, err := api.CallWithError("usermacro.get", params)
+	if err != nil {
+		return
+	}
+
+	decodeResult(response.Result, &res)
+	return
+}
+
+// Wrapper for usermacro.createglobal: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/createglobal
+func (api *API) GlobalMacrosCreate(macros GlobalMacros) (err error) {
+	response, err := api.CallWithError("usermacro.createglobal", macros)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	globalmacroids := result["globalmacroids"].([]interface{})
+	for i, id := range globalmacroids {
+		macros[i].GlobalMacroId = id.(string)
+	}
+	return
+}
+
+// Wrapper for usermacro.updateglobal: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/updateglobal
+func (api *API) GlobalMacrosUpdate(macros GlobalMacros) (err error) {
+	response, err := api.CallWithError("usermacro.updateglobal", macros)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	globalmacroids := result["globalmacroids"].([]interface{})
+	if len(macros) != len(globalmacroids) {
+		err = &ExpectedMore{len(macros), len(globalmacroids)}
+	}
+	return
+}
+
+// Wrapper for usermacro.deleteglobal: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/deleteglobal
+// Cleans GlobalMacroId in all macros elements if call succeed.
+func (api *API) GlobalMacrosDelete(macros GlobalMacros) (err error) {
+	ids := make([]string, len(macros))
+	for i, macro := range macros {
+		ids[i] = macro.GlobalMacroId
+	}
+
+	err = api.GlobalMacrosDeleteByIds(ids)
+	if err == nil {
+		for i := range macros {
+			macros[i].GlobalMacroId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for usermacro.deleteglobal: https://www.zabbix.com/documentation/2.0/manual/appendix/api/usermacro/deleteglobal
+func (api *API) GlobalMacrosDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("usermacro.deleteglobal", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	globalmacroids := result["globalmacroids"].([]interface{})
+	if len(ids) != len(globalmacroids) {
+		err = &ExpectedMore{len(ids), len(globalmacroids)}
+	}
+	return
+}
+
+// Returns effective value of macro (like {$LOW_SPACE} or {$LOW_SPACE:"/var"}) for given host,
+// looking at host macros, then at macros of linked templates level by level, then at global macros.
+// Values of secret macros are not returned by API, so they resolve to empty string.
+func (api *API) UserMacroResolve(hostId, macro string) (value string, err error) {
+	ids, err := api.macroChain(hostId)
+	if err != nil {
+		return
+	}
+
+	macros, err := api.UserMacrosGetByHostIds(ids)
+	if err != nil {
+		return
+	}
+	globals, err := api.GlobalMacrosGet(Params{})
+	if err != nil {
+		return
+	}
+
+	byHost := make(map[string]UserMacros, len(ids))
+	for _, m := range macros {
+		byHost[m.HostId] = append(byHost[m.HostId], m)
+	}
+	levels := make([]UserMacros, len(ids))
+	for i, id := range ids {
+		levels[i] = byHost[id]
+	}
+
+	value, ok := ResolveUserMacro(macro, levels, globals)
+	if !ok {
+		err = MacroNotFound(macro)
+	}
+	return
+}
+
+// Returns ids of host and templates it inherits macros from, in resolution order:
+// host itself, then first level templates sorted by id, then their templates and so on.
+func (api *API) macroChain(hostId string) (ids []string, err error) {
+	ids = []string{hostId}
+	seen := map[string]bool{hostId: true}
+
+	response, err := api.CallWithError("host.get", Params{
+		"hostids":               hostId,
+		"output":                []string{"hostid"},
+		"selectParentTemplates": []string{"templateid"},
+	})
+	if err != nil {
+		return
+	}
+
+	level := parentTemplateIds(response.Result.([]interface{}), seen)
+	for len(level) > 0 {
+		ids = append(ids, level...)
+		response, err = api.CallWithError("template.get", Params{
+			"templateids":           level,
+			"output":                []string{"templateid"},
+			"selectParentTemplates": []string{"templateid"},
+		})
+		if err != nil {
+			return
+		}
+		level = parentTemplateIds(response.Result.([]interface{}), seen)
+	}
+	return
+}
+
+func parentTemplateIds(result []interface{}, seen map[string]bool) (ids []string) {
+	for _, r := range result {
+		parents, _ := r.(map[string]interface{})["parentTemplates"].([]interface{})
+		for _, p := range parents {
+			id := p.(map[string]interface{})["templateid"].(string)
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) < len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+	return
+}
+
+// Resolves macro using macros of host and its templates (levels, in resolution order) and global macros.
+// Macro with context is looked up first with the same context (static one first, then regex: contexts)
+// on all levels; if not found, macro without context is used.
+func ResolveUserMacro(macro string, levels []UserMacros, globals GlobalMacros) (value string, ok bool) {
+	name, context, hasContext := parseMacro(macro)
+
+	all := make([]UserMacros, len(levels), len(levels)+1)
+	copy(all, levels)
+	global := make(UserMacros, len(globals))
+	for i, g := range globals {
+		global[i] = UserMacro{Macro: g.Macro, Value: g.Value, Type: g.Type}
+	}
+	all = append(all, global)
+
+	if hasContext {
+		for _, level := range all {
+			if value, ok = lookupMacro(level, name, context); ok {
+				return
+			}
+		}
+	}
+	for _, level := range all {
+		for _, m := range level {
+			n, _, c := parseMacro(m.Macro)
+			if n == name && !c {
+				return m.Value, true
+			}
+		}
+	}
+	return
+}
+
+func lookupMacro(macros UserMacros, name, context string) (value string, ok bool) {
+	for _, m := range macros {
+		n, c, hasContext := parseMacro(m.Macro)
+		if n == name && hasContext && !strings.HasPrefix(c, "regex:") && c == context {
+			return m.Value, true
+		}
+	}
+	for _, m := range macros {
+		n, c, hasContext := parseMacro(m.Macro)
+		if n != name || !hasContext || !strings.HasPrefix(c, "regex:") {
+			continue
+		}
+		re, err := regexp.Compile(unquoteMacroContext(strings.TrimPrefix(c, "regex:")))
+		if err == nil && re.MatchString(context) {
+			return m.Value, true
+		}
+	}
+	return
+}
+
+// Splits {$NAME:context} into name and unquoted context.
+// Prefix "regex:" is kept in context so regex and static contexts are not mixed up.
+func parseMacro(macro string) (name, context string, hasContext bool) {
+	s := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(macro), "{$"), "}")
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return s, "", false
+	}
+	name, context = s[:i], strings.TrimLeft(s[i+1:], " ")
+	if strings.HasPrefix(context, "regex:") {
+		return name, "regex:" + strings.TrimLeft(context[len("regex:"):], " "), true
+	}
+	return name, unquoteMacroContext(context), true
+}
+
+func unquoteMacroContext(context string) string {
+	if len(context) < 2 || context[0] != '"' || context[len(context)-1] != '"' {
+		return context
+	}
+	return strings.Replace(context[1:len(context)-1], `\"`, `"`, -1)
+}
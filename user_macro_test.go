@@ -0,0 +1,161 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/AlekSi/zabbix/internal/apiutil"
+)
+
+func CreateUserMacro(host *Host, t *testing.T) *UserMacro {
+	macros := UserMacros{{HostId: host.HostId, Macro: fmt.Sprintf("{$TEST_%d}", rand.Int()), Value: "42"}}
+	err := getAPI(t).UserMacrosCreate(macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &macros[0]
+}
+
+func DeleteUserMacro(macro *UserMacro, t *testing.T) {
+	err := getAPI(t).UserMacrosDelete(UserMacros{*macro})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserMacros(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	macro := CreateUserMacro(host, t)
+	if macro.HostMacroId == "" {
+		t.Errorf("Id is empty: %#v", macro)
+	}
+
+	macro.Value = "43"
+	err := api.UserMacrosUpdate(UserMacros{*macro})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	macros, err := api.UserMacrosGetByHostIds([]string{host.HostId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(macros) != 1 || macros[0].Value != "43" {
+		t.Errorf("Bad macros: %#v", macros)
+	}
+
+	value, err := api.UserMacroResolve(host.HostId, macro.Macro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "43" {
+		t.Errorf("Bad value: %s", value)
+	}
+
+	DeleteUserMacro(macro, t)
+}
+
+func TestGlobalMacros(t *testing.T) {
+	api := getAPI(t)
+
+	macros := GlobalMacros{{Macro: fmt.Sprintf("{$TEST_%d}", rand.Int()), Value: "42"}}
+	err := api.GlobalMacrosCreate(macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if macros[0].GlobalMacroId == "" {
+		t.Errorf("Id is empty: %#v", macros[0])
+	}
+
+	err = api.GlobalMacrosDelete(macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveUserMacro(t *testing.T) {
+	host := UserMacros{{Macro: "{$LOW_SPACE}", Value: "5"}}
+	template := UserMacros{
+		{Macro: `{$LOW_SPACE:"/var"}`, Value: "10"},
+		{Macro: `{$LOW_SPACE:regex:"^/srv"}`, Value: "15"},
+		{Macro: "{$TIMEOUT}", Value: "3s"},
+	}
+	globals := GlobalMacros{{Macro: "{$TIMEOUT}", Value: "10s"}, {Macro: "{$GLOBAL}", Value: "g"}}
+	levels := []UserMacros{host, template}
+
+	for macro, expected := range map[string]string{
+		"{$LOW_SPACE}":          "5",
+		`{$LOW_SPACE:"/var"}`:   "10",
+		"{$LOW_SPACE:/var}":     "10",
+		`{$LOW_SPACE:"/srv/a"}`: "15",
+		`{$LOW_SPACE:"/home"}`:  "5",
+		"{$TIMEOUT}":            "3s",
+		"{$GLOBAL}":             "g",
+	} {
+		value, ok := ResolveUserMacro(macro, levels, globals)
+		if !ok || value != expected {
+			t.Errorf("%s: expected %q, got %q (%v)", macro, expected, value, ok)
+		}
+	}
+
+	if _, ok := ResolveUserMacro("{$MISSING}", levels, globals); ok {
+		t.Error("Resolved missing macro")
+	}
+}
+
+func TestUserMacroType(t *testing.T) {
+	api := getAPI(t)
+	if version, err := api.Version(); err != nil || apiutil.OlderThan(version, 5, 0) {
+		t.Skipf("Macro types are not supported by %s (%v)", version, err)
+	}
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	secret, text := SecretMacro, TextMacro
+	macros := UserMacros{{HostId: host.HostId, Macro: "{$LALA_SECRET}", Value: "42", Type: &secret}}
+	err := api.UserMacrosCreate(macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer api.UserMacrosDelete(macros)
+
+	macros[0].Type = &text
+	err = api.UserMacrosUpdate(macros)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	macros2, err := api.UserMacrosGetByHostIds([]string{host.HostId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(macros2) != 1 || macros2[0].Type == nil || *macros2[0].Type != TextMacro || macros2[0].Value != "42" {
+		t.Errorf("Bad macros: %#v", macros2)
+	}
+}
+
+func TestMacroTypeJSON(t *testing.T) {
+	text := TextMacro
+	b, err := json.Marshal(UserMacros{{Macro: "{$A}", Value: "1"}, {Macro: "{$B}", Value: "2", Type: &text}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"macro":"{$A}","value":"1"},{"macro":"{$B}","value":"2","type":0}]`
+	if string(b) != expected {
+		t.Errorf("Expected %s, got %s", expected, b)
+	}
+}